@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -21,6 +22,9 @@ type ChangeImpl struct {
 
 	createdAt time.Time
 
+	// data (if set) is used instead of fetching app change again
+	data map[string]string
+
 	appChangesMaxToKeep int
 }
 
@@ -47,6 +51,77 @@ func (c *ChangeImpl) Succeed() error {
 	})
 }
 
+func (c *ChangeImpl) Resources() ([]ctlres.Resource, error) {
+	return c.recorded(NewChangeResourcesFromData)
+}
+
+func (c *ChangeImpl) RecordResources(rs []ctlres.Resource) error {
+	changeResources, err := NewChangeResources(rs)
+	if err != nil {
+		return err
+	}
+	return c.record(changeResources)
+}
+
+func (c *ChangeImpl) Config() ([]ctlres.Resource, error) {
+	return c.recorded(NewChangeConfigResourcesFromData)
+}
+
+func (c *ChangeImpl) RecordConfig(rs []ctlres.Resource) error {
+	changeResources, err := NewChangeConfigResources(rs)
+	if err != nil {
+		return err
+	}
+	return c.record(changeResources)
+}
+
+func (c *ChangeImpl) recorded(fromDataFunc func(map[string]string) (ChangeResources, bool)) ([]ctlres.Resource, error) {
+	if len(c.name) == 0 {
+		return nil, nil
+	}
+
+	data := c.data
+
+	if data == nil {
+		change, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting app change: %w", err)
+		}
+		data = change.Data
+	}
+
+	changeResources, found := fromDataFunc(data)
+	if !found {
+		return nil, nil
+	}
+
+	return changeResources.Resources()
+}
+
+func (c *ChangeImpl) record(changeResources ChangeResources) error {
+	if c.appChangesMaxToKeep == 0 {
+		return nil
+	}
+
+	change, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Get(context.TODO(), c.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting app change: %w", err)
+	}
+
+	if change.Data == nil {
+		change.Data = map[string]string{}
+	}
+
+	changeResources.AddToData(change.Data)
+
+	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(context.TODO(), change, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Recording app change resources: %w", err)
+	}
+
+	return nil
+}
+
 func (c *ChangeImpl) Delete() error {
 	err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
 	if err != nil {
@@ -71,7 +146,14 @@ func (c *ChangeImpl) update(doFunc func(*ChangeMeta)) error {
 	doFunc(&meta)
 
 	c.meta = meta
-	change.Data = meta.AsData()
+
+	// Preserve other data (e.g. recorded resources)
+	if change.Data == nil {
+		change.Data = map[string]string{}
+	}
+	for k, v := range meta.AsData() {
+		change.Data[k] = v
+	}
 
 	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(context.TODO(), change, metav1.UpdateOptions{})
 	if err != nil {
@@ -90,3 +172,8 @@ func (NoopChange) Meta() ChangeMeta { return ChangeMeta{} }
 func (NoopChange) Fail() error      { return nil }
 func (NoopChange) Succeed() error   { return nil }
 func (NoopChange) Delete() error    { return nil }
+
+func (NoopChange) Resources() ([]ctlres.Resource, error)   { return nil, nil }
+func (NoopChange) RecordResources([]ctlres.Resource) error { return nil }
+func (NoopChange) Config() ([]ctlres.Resource, error)      { return nil, nil }
+func (NoopChange) RecordConfig([]ctlres.Resource) error    { return nil }
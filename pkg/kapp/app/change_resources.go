@@ -0,0 +1,123 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	changeResourcesDataKey = "resources"
+	changeConfigDataKey    = "config"
+
+	// Leave room for change meta within ConfigMap size limit (1MiB)
+	changeResourcesMaxSize = 900 * 1024
+)
+
+// ChangeResources is a compressed snapshot of resources
+// recorded within an app change (stored as gzipped, base64 encoded v1/List).
+type ChangeResources struct {
+	key  string
+	data string
+}
+
+func NewChangeResources(rs []ctlres.Resource) (ChangeResources, error) {
+	return newChangeResources(changeResourcesDataKey, rs)
+}
+
+// NewChangeConfigResources returns snapshot of kapp config resources
+// (recorded separately since it's used by commands other than rollback)
+func NewChangeConfigResources(rs []ctlres.Resource) (ChangeResources, error) {
+	return newChangeResources(changeConfigDataKey, rs)
+}
+
+func newChangeResources(key string, rs []ctlres.Resource) (ChangeResources, error) {
+	items := []interface{}{}
+
+	for _, res := range rs {
+		items = append(items, res.DeepCopyRaw())
+	}
+
+	listBs, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	})
+	if err != nil {
+		return ChangeResources{}, fmt.Errorf("Encoding app change resources: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+
+	_, err = gzipWriter.Write(listBs)
+	if err != nil {
+		return ChangeResources{}, fmt.Errorf("Compressing app change resources: %w", err)
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return ChangeResources{}, fmt.Errorf("Compressing app change resources: %w", err)
+	}
+
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	if len(data) > changeResourcesMaxSize {
+		return ChangeResources{}, fmt.Errorf("Expected compressed app change resources "+
+			"to be at most %d bytes, but was %d bytes", changeResourcesMaxSize, len(data))
+	}
+
+	return ChangeResources{key, data}, nil
+}
+
+func NewChangeResourcesFromData(data map[string]string) (ChangeResources, bool) {
+	return newChangeResourcesFromData(changeResourcesDataKey, data)
+}
+
+func NewChangeConfigResourcesFromData(data map[string]string) (ChangeResources, bool) {
+	return newChangeResourcesFromData(changeConfigDataKey, data)
+}
+
+func newChangeResourcesFromData(key string, data map[string]string) (ChangeResources, bool) {
+	resourcesData, found := data[key]
+	return ChangeResources{key, resourcesData}, found
+}
+
+func (r ChangeResources) Resources() ([]ctlres.Resource, error) {
+	gzipBs, err := base64.StdEncoding.DecodeString(r.data)
+	if err != nil {
+		return nil, fmt.Errorf("Decoding app change resources: %w", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(gzipBs))
+	if err != nil {
+		return nil, fmt.Errorf("Decompressing app change resources: %w", err)
+	}
+
+	defer gzipReader.Close()
+
+	listBs, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, fmt.Errorf("Decompressing app change resources: %w", err)
+	}
+
+	rs, err := ctlres.NewResourcesFromBytes(listBs)
+	if err != nil {
+		return nil, fmt.Errorf("Decoding app change resources: %w", err)
+	}
+
+	return rs, nil
+}
+
+func (r ChangeResources) AddToData(data map[string]string) {
+	data[r.key] = r.data
+}
@@ -6,6 +6,7 @@ package app
 import (
 	"time"
 
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -41,5 +42,13 @@ type Change interface {
 	Fail() error
 	Succeed() error
 
+	// Resources returns recorded snapshot of resources (nil if not recorded)
+	Resources() ([]ctlres.Resource, error)
+	RecordResources([]ctlres.Resource) error
+
+	// Config returns recorded kapp config resources (nil if not recorded)
+	Config() ([]ctlres.Resource, error)
+	RecordConfig([]ctlres.Resource) error
+
 	Delete() error
 }
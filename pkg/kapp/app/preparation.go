@@ -135,6 +135,32 @@ func (a Preparation) addNonce(resources []ctlres.Resource) ([]ctlres.Resource, e
 	return resources, nil
 }
 
+// WithoutNonces returns copies of resources with nonce annotation values reset
+// so that prepared resources could be prepared again (e.g. during rollback)
+func WithoutNonces(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	resetNonceMod := ctlres.StringMapAppendMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+		KVs:             map[string]string{nonceAnnKey: ""},
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range resources {
+		res = res.DeepCopy()
+
+		if _, found := res.Annotations()[nonceAnnKey]; found {
+			err := resetNonceMod.Apply(res)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, res)
+	}
+	return result, nil
+}
+
 func (a Preparation) validateBasicInfo(resources []ctlres.Resource) error {
 	var errs []error
 
@@ -615,6 +615,22 @@ func (c appTrackingChange) Delete() error {
 	return c.change.Delete()
 }
 
+func (c appTrackingChange) Resources() ([]ctlres.Resource, error) {
+	return c.change.Resources()
+}
+
+func (c appTrackingChange) RecordResources(rs []ctlres.Resource) error {
+	return c.change.RecordResources(rs)
+}
+
+func (c appTrackingChange) Config() ([]ctlres.Resource, error) {
+	return c.change.Config()
+}
+
+func (c appTrackingChange) RecordConfig(rs []ctlres.Resource) error {
+	return c.change.RecordConfig(rs)
+}
+
 func (c appTrackingChange) syncOnApp() error {
 	return c.app.update(func(meta *Meta) {
 		meta.LastChangeName = c.change.Name()
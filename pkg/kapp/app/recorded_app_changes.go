@@ -74,6 +74,7 @@ func (a RecordedAppChanges) List() ([]Change, error) {
 			coreClient: a.coreClient,
 			meta:       NewChangeMetaFromData(change.Data),
 			createdAt:  change.CreationTimestamp.Time,
+			data:       change.Data,
 		})
 	}
 
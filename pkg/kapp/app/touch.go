@@ -3,6 +3,10 @@
 
 package app
 
+import (
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
 type Touch struct {
 	App              App
 	Description      string
@@ -10,6 +14,13 @@ type Touch struct {
 	IgnoreSuccessErr bool
 
 	AppChangesMaxToKeep int
+
+	// Resources (if specified) are recorded within app change
+	Resources []ctlres.Resource
+
+	// Config (if specified) is recorded within app change
+	// so that it could be used by commands such as drift
+	Config []ctlres.Resource
 }
 
 func (t Touch) Do(doFunc func() error) error {
@@ -23,6 +34,22 @@ func (t Touch) Do(doFunc func() error) error {
 		return err
 	}
 
+	if t.Resources != nil {
+		err = change.RecordResources(t.Resources)
+		if err != nil {
+			_ = change.Fail()
+			return err
+		}
+	}
+
+	if len(t.Config) > 0 {
+		err = change.RecordConfig(t.Config)
+		if err != nil {
+			_ = change.Fail()
+			return err
+		}
+	}
+
 	workErr := doFunc()
 	if workErr != nil {
 		_ = change.Fail()
@@ -19,16 +19,22 @@ const (
 	createStrategyPlainAnnValue                  ClusterChangeApplyStrategyOp = ""
 	createStrategyFallbackOnUpdateAnnValue       ClusterChangeApplyStrategyOp = "fallback-on-update"
 	createStrategyFallbackOnUpdateOrNoopAnnValue ClusterChangeApplyStrategyOp = "fallback-on-update-or-noop"
+	createStrategyServerSideApplyAnnValue        ClusterChangeApplyStrategyOp = "server-side-apply"
 
 	updateStrategyAnnKey                                                 = "kapp.k14s.io/update-strategy"
 	updateStrategyPlainAnnValue             ClusterChangeApplyStrategyOp = ""
 	updateStrategyFallbackOnReplaceAnnValue ClusterChangeApplyStrategyOp = "fallback-on-replace"
 	updateStrategyAlwaysReplaceAnnValue     ClusterChangeApplyStrategyOp = "always-replace"
 	updateStrategySkipAnnValue              ClusterChangeApplyStrategyOp = "skip"
+	updateStrategyServerSideApplyAnnValue   ClusterChangeApplyStrategyOp = "server-side-apply"
 )
 
 type AddOrUpdateChangeOpts struct {
 	DefaultUpdateStrategy string
+
+	// Used by server-side-apply strategies
+	FieldManager   string
+	ForceConflicts bool
 }
 
 type AddOrUpdateChange struct {
@@ -50,6 +56,13 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		strategy, found := newRes.Annotations()[createStrategyAnnKey]
 		if !found {
 			strategy = string(createStrategyPlainAnnValue)
+
+			// Resources that will be updated via server-side apply
+			// should be created via server-side apply as well so that
+			// field ownership is recorded for the same field manager
+			if c.updateStrategy(newRes) == string(updateStrategyServerSideApplyAnnValue) {
+				strategy = string(createStrategyServerSideApplyAnnValue)
+			}
 		}
 
 		switch ClusterChangeApplyStrategyOp(strategy) {
@@ -62,6 +75,9 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		case createStrategyFallbackOnUpdateOrNoopAnnValue:
 			return AddOrFallbackOnUpdateOrNoopStrategy{newRes, c}, nil
 
+		case createStrategyServerSideApplyAnnValue:
+			return AddServerSideApplyStrategy{c}, nil
+
 		default:
 			return nil, fmt.Errorf("Unknown create strategy: %s", strategy)
 		}
@@ -69,10 +85,7 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 	case ctldiff.ChangeOpUpdate:
 		newRes := c.change.NewResource()
 
-		strategy, found := newRes.Annotations()[updateStrategyAnnKey]
-		if !found {
-			strategy = c.opts.DefaultUpdateStrategy
-		}
+		strategy := c.updateStrategy(newRes)
 
 		switch ClusterChangeApplyStrategyOp(strategy) {
 		case updateStrategyPlainAnnValue:
@@ -87,6 +100,9 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		case updateStrategySkipAnnValue:
 			return UpdateSkipStrategy{c}, nil
 
+		case updateStrategyServerSideApplyAnnValue:
+			return UpdateServerSideApplyStrategy{c}, nil
+
 		default:
 			return nil, fmt.Errorf("Unknown update strategy: %s", strategy)
 		}
@@ -96,6 +112,14 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 	}
 }
 
+func (c AddOrUpdateChange) updateStrategy(newRes ctlres.Resource) string {
+	strategy, found := newRes.Annotations()[updateStrategyAnnKey]
+	if !found {
+		strategy = c.opts.DefaultUpdateStrategy
+	}
+	return strategy
+}
+
 func (c AddOrUpdateChange) replace() error {
 	// TODO do we have to wait for delete to finish?
 	err := c.identifiedResources.Delete(c.change.ExistingResource())
@@ -330,6 +354,20 @@ func (c AddOrFallbackOnUpdateOrNoopStrategy) Apply() error {
 	return c.aou.recordAppliedResource(createdRes)
 }
 
+type AddServerSideApplyStrategy struct {
+	aou AddOrUpdateChange
+}
+
+func (c AddServerSideApplyStrategy) Op() ClusterChangeApplyStrategyOp {
+	return createStrategyServerSideApplyAnnValue
+}
+
+func (c AddServerSideApplyStrategy) Apply() error { return c.aou.serverSideApply() }
+
+func (c AddServerSideApplyStrategy) FieldOwnershipConflicts() (FieldOwnershipConflicts, error) {
+	return c.aou.serverSideApplyConflicts()
+}
+
 type UpdatePlainStrategy struct {
 	newRes ctlres.Resource
 	aou    AddOrUpdateChange
@@ -389,6 +427,20 @@ func (c UpdateAlwaysReplaceStrategy) Apply() error {
 	return c.aou.replace()
 }
 
+type UpdateServerSideApplyStrategy struct {
+	aou AddOrUpdateChange
+}
+
+func (c UpdateServerSideApplyStrategy) Op() ClusterChangeApplyStrategyOp {
+	return updateStrategyServerSideApplyAnnValue
+}
+
+func (c UpdateServerSideApplyStrategy) Apply() error { return c.aou.serverSideApply() }
+
+func (c UpdateServerSideApplyStrategy) FieldOwnershipConflicts() (FieldOwnershipConflicts, error) {
+	return c.aou.serverSideApplyConflicts()
+}
+
 type UpdateSkipStrategy struct {
 	aou AddOrUpdateChange
 }
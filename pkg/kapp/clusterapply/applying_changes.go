@@ -69,6 +69,12 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				defer applyThrottle.Done()
 
 				clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+
+				notifyProgress(c.ui, ProgressEvent{
+					Type:   ProgressEventTypeApplyStart,
+					Change: NewProgressEventChange(clusterChange),
+				})
+
 				retryable, descMsgs, err := clusterChange.Apply()
 
 				applyCh <- applyResult{
@@ -88,6 +94,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			result := <-applyCh
 
 			c.ui.Notify(result.DescMsgs)
+			c.notifyApplyFinish(result)
 
 			if result.Err != nil {
 				lastErr = result.Err
@@ -128,6 +135,22 @@ func (c *ApplyingChanges) Complete() error {
 	return nil
 }
 
+func (c *ApplyingChanges) notifyApplyFinish(result applyResult) {
+	event := ProgressEvent{
+		Type:   ProgressEventTypeApplyFinish,
+		Change: NewProgressEventChange(result.ClusterChange),
+		State:  "ok",
+	}
+	if result.Err != nil {
+		event.State = "error"
+		if result.Retryable {
+			event.State = "retryable"
+		}
+		event.Message = result.Err.Error()
+	}
+	notifyProgress(c.ui, event)
+}
+
 func (c *ApplyingChanges) nonAppliedChanges(allChanges []*ctldgraph.Change) []*ctldgraph.Change {
 	var result []*ctldgraph.Change
 	for _, change := range allChanges {
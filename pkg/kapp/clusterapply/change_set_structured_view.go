@@ -0,0 +1,114 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+)
+
+const (
+	ChangeSetViewFormatText      = "text"
+	ChangeSetViewFormatJSON      = "json"
+	ChangeSetViewFormatUnified   = "unified"
+	ChangeSetViewFormatJSONPatch = "json-patch"
+	ChangeSetViewFormatSemantic  = "semantic"
+)
+
+// ChangeSetStructuredDoc is printed when changes are requested in json or json-patch format
+type ChangeSetStructuredDoc struct {
+	Changes []ChangeStructuredDoc `json:"changes"`
+}
+
+type ChangeStructuredDoc struct {
+	Resource   string               `json:"resource"`
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Namespace  string               `json:"namespace,omitempty"`
+	Name       string               `json:"name"`
+	ApplyOp    ClusterChangeApplyOp `json:"applyOp"`
+	WaitOp     ClusterChangeWaitOp  `json:"waitOp"`
+
+	// Only one of below is populated depending on format
+	Fields    ctldiff.FieldChanges  `json:"fields,omitempty"`
+	JSONPatch []ctldiff.JSONPatchOp `json:"jsonPatch,omitempty"`
+}
+
+func (v *ChangeSetView) printChangesStructured(ui ui.UI) error {
+	doc := ChangeSetStructuredDoc{Changes: []ChangeStructuredDoc{}}
+
+	for _, view := range v.changeViews {
+		existingRes, newRes, err := view.ConfigurableTextDiff().Resources(v.maskRules, v.opts.Mask)
+		if err != nil {
+			return err
+		}
+
+		res := view.Resource()
+		fieldChanges := ctldiff.NewFieldChanges(existingRes, newRes)
+
+		changeDoc := ChangeStructuredDoc{
+			Resource:   res.Description(),
+			APIVersion: res.APIVersion(),
+			Kind:       res.Kind(),
+			Namespace:  res.Namespace(),
+			Name:       res.Name(),
+			ApplyOp:    view.ApplyOp(),
+			WaitOp:     view.WaitOp(),
+		}
+
+		switch v.opts.Format {
+		case ChangeSetViewFormatJSONPatch:
+			changeDoc.JSONPatch = fieldChanges.JSONPatch()
+		default:
+			changeDoc.Fields = fieldChanges
+		}
+
+		doc.Changes = append(doc.Changes, changeDoc)
+	}
+
+	docBs, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ui.PrintBlock(append(docBs, '\n'))
+	return nil
+}
+
+func (v *ChangeSetView) printChangesUnified(ui ui.UI) error {
+	for _, view := range v.changeViews {
+		existingRes, newRes, err := view.ConfigurableTextDiff().Resources(v.maskRules, v.opts.Mask)
+		if err != nil {
+			return err
+		}
+
+		diffStr, err := ctldiff.NewUnifiedDiff(existingRes, newRes, v.opts.Context).String()
+		if err != nil {
+			return fmt.Errorf("Calculating diff for %s: %w", view.Resource().Description(), err)
+		}
+
+		ui.PrintBlock([]byte(diffStr))
+	}
+	return nil
+}
+
+func (v *ChangeSetView) printChangesSemantic(ui ui.UI) error {
+	for _, view := range v.changeViews {
+		existingRes, newRes, err := view.ConfigurableTextDiff().Resources(v.maskRules, v.opts.Mask)
+		if err != nil {
+			return err
+		}
+
+		changes := ctldiff.NewSemanticDiff(existingRes, newRes, v.mergeKeyRules).Changes()
+
+		ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+		if len(changes) > 0 {
+			ui.PrintBlock([]byte(changes.String()))
+		}
+	}
+	return nil
+}
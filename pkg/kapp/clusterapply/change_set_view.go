@@ -18,21 +18,40 @@ type ChangeSetViewOpts struct {
 	Summary     bool
 	Changes     bool
 	ChangesYAML bool
+	Format      string
 	ctldiff.TextDiffViewOpts
+
+	// FieldOwnershipConflicts checks server-side apply changes
+	// for conflicts via dry run (one request per change)
+	FieldOwnershipConflicts bool
+}
+
+// Validate checks that changes format is known
+func (o ChangeSetViewOpts) Validate() error {
+	switch o.Format {
+	case "", ChangeSetViewFormatText, ChangeSetViewFormatJSON, ChangeSetViewFormatUnified,
+		ChangeSetViewFormatJSONPatch, ChangeSetViewFormatSemantic:
+		return nil
+	default:
+		return fmt.Errorf("Expected diff format to be one of: %s, %s, %s, %s, %s (given: '%s')",
+			ChangeSetViewFormatText, ChangeSetViewFormatJSON, ChangeSetViewFormatUnified,
+			ChangeSetViewFormatJSONPatch, ChangeSetViewFormatSemantic, o.Format)
+	}
 }
 
 type ChangeSetView struct {
-	changeViews []ChangeView
-	maskRules   []ctlconf.DiffMaskRule
-	opts        ChangeSetViewOpts
+	changeViews   []ChangeView
+	maskRules     []ctlconf.DiffMaskRule
+	mergeKeyRules []ctlconf.DiffListMergeKeyRule
+	opts          ChangeSetViewOpts
 
 	changesView *ChangesView
 }
 
-func NewChangeSetView(changeViews []ChangeView,
-	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) *ChangeSetView {
+func NewChangeSetView(changeViews []ChangeView, maskRules []ctlconf.DiffMaskRule,
+	mergeKeyRules []ctlconf.DiffListMergeKeyRule, opts ChangeSetViewOpts) *ChangeSetView {
 
-	return &ChangeSetView{changeViews, maskRules, opts, nil}
+	return &ChangeSetView{changeViews, maskRules, mergeKeyRules, opts, nil}
 }
 
 func (v *ChangeSetView) Print(ui ui.UI) {
@@ -40,20 +59,40 @@ func (v *ChangeSetView) Print(ui ui.UI) {
 		v.printChangesYAML(ui)
 	}
 	if v.opts.Changes {
-		for _, view := range v.changeViews {
-			textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
-			ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
-			ui.PrintBlock([]byte(textDiffView.String()))
+		var err error
+
+		switch v.opts.Format {
+		case ChangeSetViewFormatJSON, ChangeSetViewFormatJSONPatch:
+			err = v.printChangesStructured(ui)
+		case ChangeSetViewFormatUnified:
+			err = v.printChangesUnified(ui)
+		case ChangeSetViewFormatSemantic:
+			err = v.printChangesSemantic(ui)
+		default:
+			v.printChangesText(ui)
+		}
+
+		if err != nil {
+			ui.ErrorLinef("Error showing changes: %s", err)
 		}
 	}
 
-	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true, countsView: NewChangesCountsView()}
+	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true,
+		FieldOwnershipConflicts: v.opts.FieldOwnershipConflicts, countsView: NewChangesCountsView()}
 
 	if v.opts.Summary {
 		v.changesView.Print(ui)
 	}
 }
 
+func (v *ChangeSetView) printChangesText(ui ui.UI) {
+	for _, view := range v.changeViews {
+		textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
+		ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+		ui.PrintBlock([]byte(textDiffView.String()))
+	}
+}
+
 func (v *ChangeSetView) Summary() string {
 	return v.changesView.Summary() // assumes Print was used before
 }
@@ -23,12 +23,17 @@ type ChangeView interface {
 	ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error)
 	WaitOp() ClusterChangeWaitOp
 	ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff
+	FieldOwnershipConflicts() (FieldOwnershipConflicts, error)
+	DryRunResult() *DryRunResult
 }
 
 type ChangesView struct {
 	ChangeViews []ChangeView
 	Sort        bool
 
+	// FieldOwnershipConflicts shows conflicts for server-side apply changes
+	FieldOwnershipConflicts bool
+
 	countsView *ChangesCountsView
 }
 
@@ -45,6 +50,10 @@ func (v *ChangesView) Print(ui ui.UI) {
 	opStrategyHeader := uitable.NewHeader("Op strategy")
 	opStrategyHeader.Title = "Op st."
 
+	// Only shown when changes were sent as server-side dry runs
+	dryRunHeader := uitable.NewHeader("Dry run")
+	dryRunHeader.Hidden = true
+
 	table := uitable.Table{
 		Title: "Changes",
 		// TODO do not show total number of "changes" as it may
@@ -62,6 +71,7 @@ func (v *ChangesView) Print(ui ui.UI) {
 			uitable.NewHeader("Wait to"),
 			reconcileStateHeader,
 			reconcileInfoHeader,
+			dryRunHeader,
 		},
 	}
 
@@ -77,6 +87,9 @@ func (v *ChangesView) Print(ui ui.UI) {
 		table.FillFirstColumn = true
 	}
 
+	var conflictNotes []string
+	var dryRunNotes []string
+
 	for _, view := range v.ChangeViews {
 		resource := view.Resource()
 		v.countsView.Add(view.ApplyOp(), view.WaitOp())
@@ -99,12 +112,30 @@ func (v *ChangesView) Print(ui ui.UI) {
 			)
 		}
 
+		var conflicts FieldOwnershipConflicts
+		var conflictsErr error
+
+		if v.FieldOwnershipConflicts {
+			conflicts, conflictsErr = view.FieldOwnershipConflicts()
+		}
+
 		row = append(row,
 			v.applyOpCode(view.ApplyOp()),
-			v.applyStrategyOpCode(view),
+			v.applyStrategyOpCodeWithConflicts(view, conflicts, conflictsErr),
 			v.waitOpCode(view.WaitOp()),
 		)
 
+		switch {
+		case conflictsErr != nil:
+			conflictNotes = append(conflictNotes, fmt.Sprintf(
+				"Field ownership conflicts for %s could not be determined: %s", resource.Description(), conflictsErr))
+		case len(conflicts) > 0:
+			conflictNotes = append(conflictNotes, fmt.Sprintf("Field ownership conflicts for %s:", resource.Description()))
+			for _, conflict := range conflicts {
+				conflictNotes = append(conflictNotes, "- "+conflict.String())
+			}
+		}
+
 		if view.ClusterOriginalResource() != nil {
 			syncVal := NewValueResourceConverged(view.ClusterOriginalResource())
 			row = append(row, syncVal.StateVal, syncVal.ReasonVal)
@@ -115,10 +146,20 @@ func (v *ChangesView) Print(ui ui.UI) {
 			)
 		}
 
+		dryRunResult := view.DryRunResult()
+		if dryRunResult != nil {
+			table.Header[len(table.Header)-1].Hidden = false
+			dryRunNotes = append(dryRunNotes, v.dryRunNotes(resource, *dryRunResult)...)
+		}
+
+		row = append(row, v.dryRunCode(dryRunResult))
+
 		table.Rows = append(table.Rows, row)
 	}
 
 	table.Notes = append(table.Notes, v.countsView.Strings(true)...)
+	table.Notes = append(table.Notes, conflictNotes...)
+	table.Notes = append(table.Notes, dryRunNotes...)
 
 	ui.PrintTable(table)
 }
@@ -139,6 +180,7 @@ var (
 			createStrategyPlainAnnValue:                  "",
 			createStrategyFallbackOnUpdateAnnValue:       "fallback on update",
 			createStrategyFallbackOnUpdateOrNoopAnnValue: "fallback on update or noop",
+			createStrategyServerSideApplyAnnValue:        "server-side apply",
 		},
 
 		ClusterChangeApplyOpUpdate: {
@@ -146,6 +188,7 @@ var (
 			updateStrategyFallbackOnReplaceAnnValue: "fallback on replace",
 			updateStrategyAlwaysReplaceAnnValue:     "always replace",
 			updateStrategySkipAnnValue:              "skip",
+			updateStrategyServerSideApplyAnnValue:   "server-side apply",
 		},
 
 		ClusterChangeApplyOpDelete: {
@@ -169,6 +212,12 @@ var (
 	}
 )
 
+// ApplyOpCodeUI returns user facing name of apply op (e.g. create)
+func ApplyOpCodeUI(op ClusterChangeApplyOp) string { return applyOpCodeUI[op] }
+
+// WaitOpCodeUI returns user facing name of wait op (e.g. reconcile)
+func WaitOpCodeUI(op ClusterChangeWaitOp) string { return waitOpCodeUI[op] }
+
 func (v *ChangesView) applyOpCode(op ClusterChangeApplyOp) uitable.Value {
 	switch op {
 	case ClusterChangeApplyOpAdd:
@@ -186,6 +235,20 @@ func (v *ChangesView) applyOpCode(op ClusterChangeApplyOp) uitable.Value {
 	}
 }
 
+func (v *ChangesView) applyStrategyOpCodeWithConflicts(view ChangeView,
+	conflicts FieldOwnershipConflicts, conflictsErr error) uitable.Value {
+
+	switch {
+	case conflictsErr != nil:
+		return uitable.ValueFmt{V: uitable.NewValueString(v.applyStrategyOpCode(view).String() + " (conflicts unknown)"), Error: true}
+	case len(conflicts) > 0:
+		return uitable.ValueFmt{V: uitable.NewValueString(fmt.Sprintf(
+			"%s (%d conflicts)", v.applyStrategyOpCode(view).String(), len(conflicts))), Error: true}
+	default:
+		return v.applyStrategyOpCode(view)
+	}
+}
+
 func (v *ChangesView) applyStrategyOpCode(view ChangeView) uitable.Value {
 	strategyOp, err := view.ApplyStrategyOp()
 	if err == nil {
@@ -211,6 +274,41 @@ func (v *ChangesView) waitOpCode(op ClusterChangeWaitOp) uitable.Value {
 	}
 }
 
+func (v *ChangesView) dryRunCode(result *DryRunResult) uitable.Value {
+	if result == nil {
+		return uitable.NewValueString("")
+	}
+
+	switch result.Type {
+	case DryRunResultAccepted:
+		if len(result.Defaulted) > 0 {
+			return uitable.NewValueString(fmt.Sprintf("ok (%d defaulted)", len(result.Defaulted)))
+		}
+		return uitable.NewValueString("ok")
+	case DryRunResultRejected:
+		return uitable.ValueFmt{V: uitable.NewValueString(fmt.Sprintf("rejected (%s)", result.Reason)), Error: true}
+	case DryRunResultSkipped:
+		return uitable.NewValueString("skipped")
+	default:
+		return uitable.NewValueString("???")
+	}
+}
+
+func (v *ChangesView) dryRunNotes(resource ctlres.Resource, result DryRunResult) []string {
+	switch result.Type {
+	case DryRunResultAccepted:
+		if len(result.Defaulted) > 0 {
+			return []string{fmt.Sprintf("Dry run defaulted fields for %s: %s",
+				resource.Description(), strings.Join(result.Defaulted, ", "))}
+		}
+	case DryRunResultRejected:
+		return []string{fmt.Sprintf("Dry run rejected %s: %s", resource.Description(), result.Err)}
+	case DryRunResultSkipped:
+		return []string{fmt.Sprintf("Dry run skipped %s: %s", resource.Description(), result.Reason)}
+	}
+	return nil
+}
+
 type ChangesCountsView struct {
 	applyOps map[ClusterChangeApplyOp]int
 	waitOps  map[ClusterChangeWaitOp]int
@@ -267,7 +365,7 @@ func NewValueResourceConverged(resource ctlres.Resource) ValueResourceConverged
 	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
 
 	// TODO state vs err vs output
-	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
+	state, _, err := convergedResFactory.New(resource, nil, nil).IsDoneApplying()
 	stateUI := NewDoneApplyStateUI(state, err)
 
 	stateVal := uitable.ValueFmt{V: uitable.NewValueString(stateUI.State), Error: stateUI.Error}
@@ -12,6 +12,7 @@ import (
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
@@ -67,6 +68,9 @@ type ClusterChange struct {
 
 	markedNeedsWaiting bool
 
+	fieldOwnershipConflicts *FieldOwnershipConflicts
+	dryRunResult            *DryRunResult
+
 	diffMaskRules []ctlconf.DiffMaskRule
 }
 
@@ -80,7 +84,7 @@ func NewClusterChange(change ctldiff.Change, opts ClusterChangeOpts,
 	diffMaskRules []ctlconf.DiffMaskRule) *ClusterChange {
 
 	return &ClusterChange{change, opts, identifiedResources,
-		changeFactory, changeSetFactory, convergedResFactory, ui, false, diffMaskRules}
+		changeFactory, changeSetFactory, convergedResFactory, ui, false, nil, nil, diffMaskRules}
 }
 
 func (c *ClusterChange) ApplyOp() ClusterChangeApplyOp {
@@ -146,7 +150,7 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 		// TODO associated resources
 		// If existing resource is not in a "done successful" state,
 		// indicate that this will be something we need to wait for
-		resState, _, err := c.convergedResFactory.New(c.change.ClusterOriginalResource(), nil).IsDoneApplying()
+		resState, _, err := c.convergedResFactory.New(c.change.ClusterOriginalResource(), nil, nil).IsDoneApplying()
 		if err != nil || !(resState.Done && resState.Successful) {
 			return ClusterChangeWaitOpOK
 		}
@@ -173,6 +177,29 @@ func (c *ClusterChange) ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error)
 	return strategy.Op(), nil
 }
 
+// FieldOwnershipConflicts returns fields owned by other field managers
+// that would prevent change from being applied via server-side apply
+func (c *ClusterChange) FieldOwnershipConflicts() (FieldOwnershipConflicts, error) {
+	if c.fieldOwnershipConflicts == nil {
+		strategy, err := c.applyStrategy()
+		if err != nil {
+			return nil, err
+		}
+
+		var conflicts FieldOwnershipConflicts
+
+		if conflictsStrategy, ok := strategy.(FieldOwnershipConflictsStrategy); ok {
+			conflicts, err = conflictsStrategy.FieldOwnershipConflicts()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		c.fieldOwnershipConflicts = &conflicts
+	}
+	return *c.fieldOwnershipConflicts, nil
+}
+
 func (c *ClusterChange) Apply() (bool, []string, error) {
 	descMsgs := []string{c.ApplyDescription()}
 	var retryable bool
@@ -246,6 +273,48 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 	}
 }
 
+// WaitWatchOpts returns resources that need to be watched
+// to detect changes to the state of this change while waiting.
+// Watches are scoped to this change's resource, resources associated
+// via kapp association label and resources found by wait rules queries.
+func (c *ClusterChange) WaitWatchOpts() []ctlres.WatchOpts {
+	res := c.Resource()
+
+	var nsNames []string
+	if len(res.Namespace()) > 0 {
+		nsNames = []string{res.Namespace()}
+	}
+
+	result := []ctlres.WatchOpts{{
+		GroupKinds: []schema.GroupKind{res.GroupKind()},
+		Namespaces: nsNames,
+		Name:       res.Name(),
+	}}
+
+	if c.WaitOp() == ClusterChangeWaitOpOK {
+		convergedRes := c.convergedResFactory.New(res, nil, nil)
+
+		if refs := convergedRes.AssociatedResourceRefs(); len(refs) > 0 {
+			result = append(result, ctlres.WatchOpts{
+				ResourceRefs:  refs,
+				Namespaces:    nsNames,
+				LabelSelector: ctlres.NewAssociationLabel(res).AsSelector(),
+			})
+		}
+
+		for _, query := range convergedRes.AssociatedResourcesQueries() {
+			result = append(result, ctlres.WatchOpts{
+				GroupKinds:    []schema.GroupKind{query.GroupKind},
+				Namespaces:    nsNames,
+				Name:          query.Name,
+				LabelSelector: query.LabelSelector,
+			})
+		}
+	}
+
+	return result
+}
+
 func (c *ClusterChange) ApplyDescription() string {
 	return fmt.Sprintf("%s %s", applyOpCodeUI[c.ApplyOp()], c.change.NewOrExistingResource().Description())
 }
@@ -256,6 +325,9 @@ func (c *ClusterChange) WaitDescription() string {
 
 func (c *ClusterChange) Resource() ctlres.Resource { return c.change.NewOrExistingResource() }
 
+func (c *ClusterChange) NewResource() ctlres.Resource      { return c.change.NewResource() }
+func (c *ClusterChange) ExistingResource() ctlres.Resource { return c.change.ExistingResource() }
+
 func (c *ClusterChange) ClusterOriginalResource() ctlres.Resource {
 	return c.change.ClusterOriginalResource()
 }
@@ -6,12 +6,14 @@ package clusterapply
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
 )
 
 type ClusterChangeSetOpts struct {
@@ -78,6 +80,49 @@ func (c ClusterChangeSet) Calculate() ([]*ClusterChange, *ctldgraph.ChangeGraph,
 	return clusterChanges, changesGraph, nil
 }
 
+// CalculateFromPlan builds change graph with dependencies recorded in the plan.
+// Changes must be in the same order as returned by Plan.DiffChanges.
+func (c ClusterChangeSet) CalculateFromPlan(plan Plan) ([]*ClusterChange, *ctldgraph.ChangeGraph, error) {
+	if len(c.changes) != len(plan.Changes) {
+		return nil, nil, fmt.Errorf("Expected number of changes (%d) to match number of planned changes (%d)",
+			len(c.changes), len(plan.Changes))
+	}
+
+	var clusterChanges []*ClusterChange
+	var wrappedClusterChanges []ctldgraph.ActualChange
+	var waitingFor [][]int
+
+	for i, change := range c.changes {
+		planChange := plan.Changes[i]
+		clusterChange := c.clusterChangeFactory.NewClusterChange(change)
+
+		if planChange.WaitOp == ClusterChangeWaitOpOK {
+			clusterChange.MarkNeedsWaiting()
+		}
+
+		strategyOp, err := clusterChange.ApplyStrategyOp()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if clusterChange.ApplyOp() != planChange.ApplyOp || strategyOp != planChange.ApplyStrategyOp {
+			return nil, nil, fmt.Errorf("Expected change '%s' to match planned change '%s'",
+				clusterChange.ApplyDescription(), planChange.Description)
+		}
+
+		clusterChanges = append(clusterChanges, clusterChange)
+		wrappedClusterChanges = append(wrappedClusterChanges, wrappedClusterChange{clusterChange})
+		waitingFor = append(waitingFor, planChange.WaitingFor)
+	}
+
+	changesGraph, err := ctldgraph.NewChangeGraphFromDeps(wrappedClusterChanges, waitingFor, c.logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clusterChanges, changesGraph, nil
+}
+
 func (c ClusterChangeSet) markChangesToWait(change *ctldgraph.Change) bool {
 	var needsWaiting bool
 	for _, ch := range change.WaitingFor {
@@ -101,7 +146,10 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	blockedChanges := ctldgraph.NewBlockedChanges(changesGraph)
 	applyingChanges := NewApplyingChanges(
 		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError)
-	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError)
+	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts,
+		c.clusterChangeFactory.identifiedResources, c.ui, c.opts.ExitEarlyOnWaitError)
+
+	defer waitingChanges.Stop()
 
 	var unsuccessfulChanges []string
 
@@ -127,6 +175,7 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 			err := applyingChanges.Complete()
 			if err != nil {
 				c.ui.Notify([]string{fmt.Sprintf("Blocked changes:\n%s\n", blockedChanges.WhyBlocked(blockedChanges.Blocked()))})
+				notifyBlockedProgress(c.ui, blockedChanges)
 				return err
 			}
 
@@ -146,6 +195,56 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	}
 }
 
+// DryRun sends all changes to the API server as server-side dry runs.
+// Results are recorded on each change (see ClusterChange.DryRunResult).
+func (c ClusterChangeSet) DryRun(changes []*ClusterChange) {
+	defer c.logger.DebugFunc("DryRun").Finish()
+
+	skipReasons := newDryRunSkipReasons(changes)
+	dryRunThrottle := util.NewThrottle(c.opts.ApplyingChangesOpts.Concurrency)
+
+	var wg sync.WaitGroup
+
+	for _, change := range changes {
+		switch change.ApplyOp() {
+		case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate, ClusterChangeApplyOpDelete:
+		default:
+			continue
+		}
+
+		change := change // copy
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			dryRunThrottle.Take()
+			defer dryRunThrottle.Done()
+
+			change.DryRun(skipReasons.For(change))
+		}()
+	}
+
+	wg.Wait()
+}
+
+// DryRunErr returns an error describing changes rejected during server-side dry run
+func DryRunErr(changes []*ClusterChange) error {
+	var rejectedDescs []string
+
+	for _, change := range changes {
+		if result := change.DryRunResult(); result != nil && result.IsRejected() {
+			rejectedDescs = append(rejectedDescs, fmt.Sprintf("%s: %s", change.ApplyDescription(), result.Err))
+		}
+	}
+
+	if len(rejectedDescs) > 0 {
+		return uierrs.NewSemiStructuredError(fmt.Errorf(
+			"Server-side dry run rejected %d changes: [%s]", len(rejectedDescs), strings.Join(rejectedDescs, ", ")))
+	}
+	return nil
+}
+
 func ClusterChangesAsChangeViews(changes []*ClusterChange) []ChangeView {
 	var result []ChangeView
 	for _, change := range changes {
@@ -11,6 +11,7 @@ import (
 	"github.com/cppforlife/color"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
@@ -20,15 +21,24 @@ const (
 type ConvergedResource struct {
 	res                  ctlres.Resource
 	associatedRsFunc     func(ctlres.Resource, []ctlres.ResourceRef) ([]ctlres.Resource, error)
+	queriedAssociatedRs  *queriedAssociatedRs
 	specificResFactories []SpecificResFactory
 }
 
+// queriedAssociatedRs finds associated resources declared by wait rules
+// (e.g. resources created by an operator that do not have kapp association label)
+type queriedAssociatedRs struct {
+	queries    []ctlres.AssociatedResourcesQuery
+	queriesErr error
+	listFunc   func(ctlres.Resource, []ctlres.AssociatedResourcesQuery) ([]ctlres.Resource, error)
+}
+
 type SpecificResFactory func(ctlres.Resource, []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef)
 
 func NewConvergedResource(res ctlres.Resource,
 	associatedRsFunc func(ctlres.Resource, []ctlres.ResourceRef) ([]ctlres.Resource, error),
 	specificResFactories []SpecificResFactory) ConvergedResource {
-	return ConvergedResource{res, associatedRsFunc, specificResFactories}
+	return ConvergedResource{res: res, associatedRsFunc: associatedRsFunc, specificResFactories: specificResFactories}
 }
 
 func (c ConvergedResource) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
@@ -107,7 +117,35 @@ func (c ConvergedResource) IsDoneApplying() (ctlresm.DoneApplyState, []string, e
 	return ctlresm.DoneApplyState{Done: true, Successful: true}, descMsgs, nil
 }
 
+// associatedRs returns resources associated via kapp association label
+// together with resources found by wait rules queries
 func (c ConvergedResource) associatedRs() ([]ctlres.Resource, error) {
+	labeledRs, err := c.labeledAssociatedRs()
+	if err != nil {
+		return nil, err
+	}
+
+	queriedRs, err := c.queriedAssociatedRsList()
+	if err != nil {
+		return nil, err
+	}
+
+	// Same resource may be found both ways
+	var associatedRs []ctlres.Resource
+	seenKeys := map[string]struct{}{}
+
+	for _, res := range append(labeledRs, queriedRs...) {
+		key := ctlres.NewUniqueResourceKey(res).String()
+		if _, found := seenKeys[key]; !found {
+			seenKeys[key] = struct{}{}
+			associatedRs = append(associatedRs, res)
+		}
+	}
+
+	return c.sortAssociatedRs(associatedRs), nil
+}
+
+func (c ConvergedResource) labeledAssociatedRs() ([]ctlres.Resource, error) {
 	if c.associatedRsFunc == nil {
 		return nil, nil
 	}
@@ -117,11 +155,7 @@ func (c ConvergedResource) associatedRs() ([]ctlres.Resource, error) {
 		if !reflect.ValueOf(matchedRes).IsNil() {
 			// querying the cluster (for associated res) is expensive
 			if len(associatedResRefs) > 0 {
-				associatedRs, err := c.associatedRsFunc(c.res, associatedResRefs)
-				if err != nil {
-					return nil, err
-				}
-				return c.sortAssociatedRs(associatedRs), nil
+				return c.associatedRsFunc(c.res, associatedResRefs)
 			}
 			break
 		}
@@ -129,6 +163,52 @@ func (c ConvergedResource) associatedRs() ([]ctlres.Resource, error) {
 	return nil, nil
 }
 
+func (c ConvergedResource) queriedAssociatedRsList() ([]ctlres.Resource, error) {
+	if c.queriedAssociatedRs == nil {
+		return nil, nil
+	}
+	if c.queriedAssociatedRs.queriesErr != nil {
+		return nil, c.queriedAssociatedRs.queriesErr
+	}
+	if len(c.queriedAssociatedRs.queries) == 0 || c.queriedAssociatedRs.listFunc == nil {
+		return nil, nil
+	}
+	return c.queriedAssociatedRs.listFunc(c.res, c.queriedAssociatedRs.queries)
+}
+
+// AssociatedResourceRefs returns types of associated resources
+// that are considered when determining state of this resource
+func (c ConvergedResource) AssociatedResourceRefs() []ctlres.ResourceRef {
+	for _, f := range c.specificResFactories {
+		matchedRes, associatedResRefs := f(c.res, nil)
+		if !reflect.ValueOf(matchedRes).IsNil() {
+			return associatedResRefs
+		}
+	}
+	return nil
+}
+
+// AssociatedGroupKinds returns kinds of associated resources
+// declared by wait rules that are considered when determining state of this resource
+func (c ConvergedResource) AssociatedGroupKinds() []schema.GroupKind {
+	var result []schema.GroupKind
+	if c.queriedAssociatedRs != nil {
+		for _, query := range c.queriedAssociatedRs.queries {
+			result = append(result, query.GroupKind)
+		}
+	}
+	return result
+}
+
+// AssociatedResourcesQueries returns queries declared by wait rules
+// that are used to find associated resources of this resource
+func (c ConvergedResource) AssociatedResourcesQueries() []ctlres.AssociatedResourcesQuery {
+	if c.queriedAssociatedRs != nil {
+		return c.queriedAssociatedRs.queries
+	}
+	return nil
+}
+
 func (c ConvergedResource) sortAssociatedRs(associatedRs []ctlres.Resource) []ctlres.Resource {
 	convergedResKey := ctlres.NewUniqueResourceKey(c.res).String()
 
@@ -7,25 +7,35 @@ import (
 	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type ConvergedResourceFactoryOpts struct {
 	IgnoreFailingAPIServices bool
+	// StorageClassBindingModes (if specified) are used by PVC waiter
+	// to avoid waiting for claims that are waiting for first consumer
+	StorageClassBindingModes *StorageClassBindingModes
 }
 
 type ConvergedResourceFactory struct {
-	waitRules []ctlconf.WaitRule
+	waitRules ctlresm.CustomWaitRules
 	opts      ConvergedResourceFactoryOpts
 }
 
 func NewConvergedResourceFactory(waitRules []ctlconf.WaitRule,
 	opts ConvergedResourceFactoryOpts) ConvergedResourceFactory {
-	return ConvergedResourceFactory{waitRules, opts}
+	// Wait rules are prepared once so that CEL expressions
+	// are compiled once per deploy instead of once per resource
+	return ConvergedResourceFactory{ctlresm.NewCustomWaitRules(waitRules), opts}
 }
 
+// New returns converged resource. associatedRsFunc finds resources via kapp association label,
+// while queriedAssociatedRsFunc finds associated resources declared by wait rules;
+// both are optional (e.g. not necessary when only associated resource types are of interest).
 func (f ConvergedResourceFactory) New(res ctlres.Resource,
-	associatedRsFunc func(ctlres.Resource, []ctlres.ResourceRef) ([]ctlres.Resource, error)) ConvergedResource {
+	associatedRsFunc func(ctlres.Resource, []ctlres.ResourceRef) ([]ctlres.Resource, error),
+	queriedAssociatedRsFunc func(ctlres.Resource, []ctlres.AssociatedResourcesQuery) ([]ctlres.Resource, error)) ConvergedResource {
 
 	specificResFactories := []SpecificResFactory{
 		// kapp-controller app resource waiter deals with reconciliation _and_ deletion
@@ -38,13 +48,17 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewPackagingCarvelDevV1alpha1PackageRepo(res), nil
 		},
+		// Namespace waiter describes namespace termination better than generic deletion
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewCoreV1Namespace(res), nil
+		},
 		// Deal with deletion generically since below resource waiters do not not know about that
 		// TODO shoud we make all of them deal with deletion internally?
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewDeleting(res), nil
 		},
-		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
-			return ctlresm.NewCustomWaitingResource(res, f.waitRules), nil
+		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewCustomWaitingResource(res, aRs, f.waitRules), nil
 		},
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewAPIExtensionsVxCRD(res), nil
@@ -61,6 +75,18 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewCoreV1Service(res), nil
 		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewCoreV1PVC(res, f.volumeBindingModeFunc()), nil
+		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewNetworkingV1Ingress(res), nil
+		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewAutoscalingV2HPA(res), nil
+		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewPolicyV1PDB(res), nil
+		},
 		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			// Use newly provided associated resources as they may be modified by ConvergedResource
 			return ctlresm.NewAppsV1Deployment(res, aRs), []ctlres.ResourceRef{
@@ -68,8 +94,8 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 				{schema.GroupVersionResource{Group: "", Resource: "pods"}},
 			}
 		},
-		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
-			return ctlresm.NewAppsV1DaemonSet(res), []ctlres.ResourceRef{
+		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewAppsV1DaemonSet(res, aRs), []ctlres.ResourceRef{
 				{schema.GroupVersionResource{Group: "apps", Resource: "replicasets"}},
 				{schema.GroupVersionResource{Group: "", Resource: "pods"}},
 			}
@@ -92,5 +118,21 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 		},
 	}
 
-	return NewConvergedResource(res, associatedRsFunc, specificResFactories)
+	convergedRes := NewConvergedResource(res, associatedRsFunc, specificResFactories)
+
+	queries, err := f.waitRules.AssociatedResourcesQueries(res)
+	if err != nil || len(queries) > 0 {
+		convergedRes.queriedAssociatedRs = &queriedAssociatedRs{queries, err, queriedAssociatedRsFunc}
+	}
+
+	return convergedRes
+}
+
+// volumeBindingModeFunc returns function that finds binding mode of storage class
+// (nil is returned if there is no way to query the cluster)
+func (f ConvergedResourceFactory) volumeBindingModeFunc() func(string) (storagev1.VolumeBindingMode, bool, error) {
+	if f.opts.StorageClassBindingModes == nil {
+		return nil
+	}
+	return f.opts.StorageClassBindingModes.VolumeBindingMode
 }
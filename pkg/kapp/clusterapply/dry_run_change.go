@@ -0,0 +1,260 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type DryRunResultType string
+
+const (
+	DryRunResultAccepted DryRunResultType = "accepted"
+	DryRunResultRejected DryRunResultType = "rejected"
+	DryRunResultSkipped  DryRunResultType = "skipped"
+)
+
+// DryRunResult describes how API server (including admission webhooks)
+// responded to a change sent as a server-side dry run
+type DryRunResult struct {
+	Type   DryRunResultType
+	Reason string
+
+	// Defaulted lists fields that were set by the API server
+	// (e.g. defaulting or mutating webhooks) but were not part of sent resource
+	Defaulted []string
+	Err       error
+}
+
+func (r DryRunResult) IsRejected() bool { return r.Type == DryRunResultRejected }
+
+func newDryRunResult(sentRes, returnedRes ctlres.Resource, err error) DryRunResult {
+	if err != nil {
+		return DryRunResult{Type: DryRunResultRejected, Reason: dryRunRejectedReason(err), Err: err}
+	}
+
+	return DryRunResult{
+		Type:      DryRunResultAccepted,
+		Defaulted: DryRunDefaultedFields(sentRes.UnstructuredObject(), returnedRes.UnstructuredObject()),
+	}
+}
+
+// dryRunRejectedReason describes rejection based on API status reason.
+// Admission webhooks may deny requests with any reason (or none),
+// in which case status code is used instead.
+func dryRunRejectedReason(err error) string {
+	switch apierrors.ReasonForError(err) {
+	case metav1.StatusReasonInvalid:
+		return "invalid"
+	case metav1.StatusReasonForbidden:
+		return "forbidden"
+	case metav1.StatusReasonConflict:
+		return "conflict"
+	case metav1.StatusReasonBadRequest:
+		return "bad request"
+	case metav1.StatusReasonInternalError:
+		return "internal error"
+	}
+
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Code != 0 {
+		return fmt.Sprintf("status code %d", statusErr.Status().Code)
+	}
+	return "error"
+}
+
+// DryRun sends change to the API server as a server-side dry run
+// and records result so that it could be presented to the user.
+// Non-empty skip reason indicates that change cannot be validated
+// (e.g. its namespace does not exist yet).
+func (c *ClusterChange) DryRun(skipReason string) {
+	result := c.dryRun(skipReason)
+	c.dryRunResult = &result
+}
+
+// DryRunResult returns nil if change was not sent as a dry run
+func (c *ClusterChange) DryRunResult() *DryRunResult { return c.dryRunResult }
+
+func (c *ClusterChange) dryRun(skipReason string) DryRunResult {
+	if len(skipReason) > 0 {
+		return DryRunResult{Type: DryRunResultSkipped, Reason: skipReason}
+	}
+
+	strategyOp, err := c.ApplyStrategyOp()
+	if err != nil {
+		return DryRunResult{Type: DryRunResultRejected, Reason: "error", Err: err}
+	}
+
+	resources, err := c.identifiedResources.WithDryRun()
+	if err != nil {
+		return DryRunResult{Type: DryRunResultRejected, Reason: "error", Err: err}
+	}
+
+	aou := AddOrUpdateChange{c.change, resources, c.changeFactory,
+		c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules}
+
+	switch c.ApplyOp() {
+	case ClusterChangeApplyOpAdd:
+		if strategyOp == createStrategyServerSideApplyAnnValue {
+			sentRes := c.change.AppliedResource()
+			appliedRes, err := resources.Apply(sentRes, aou.serverSideApplyOpts(c.opts.ForceConflicts))
+			return newDryRunResult(sentRes, appliedRes, err)
+		}
+
+		sentRes := c.change.NewResource()
+		createdRes, err := resources.Create(sentRes)
+		if err != nil && apierrors.IsAlreadyExists(err) && strategyOp != createStrategyPlainAnnValue {
+			return DryRunResult{Type: DryRunResultSkipped, Reason: "already exists, would fall back to update"}
+		}
+		return newDryRunResult(sentRes, createdRes, err)
+
+	case ClusterChangeApplyOpUpdate:
+		switch strategyOp {
+		case updateStrategySkipAnnValue:
+			return DryRunResult{Type: DryRunResultSkipped, Reason: "update strategy is skip"}
+
+		case updateStrategyAlwaysReplaceAnnValue:
+			return DryRunResult{Type: DryRunResultSkipped, Reason: "replace cannot be dry run"}
+
+		case updateStrategyServerSideApplyAnnValue:
+			sentRes := c.change.AppliedResource()
+			appliedRes, err := resources.Apply(sentRes, aou.serverSideApplyOpts(c.opts.ForceConflicts))
+			return newDryRunResult(sentRes, appliedRes, err)
+		}
+
+		sentRes := c.change.NewResource()
+		updatedRes, err := resources.Update(sentRes)
+		if err != nil && apierrors.IsInvalid(err) && strategyOp == updateStrategyFallbackOnReplaceAnnValue {
+			return DryRunResult{Type: DryRunResultSkipped, Reason: "invalid update, would fall back to replace"}
+		}
+		return newDryRunResult(sentRes, updatedRes, err)
+
+	case ClusterChangeApplyOpDelete:
+		if strategyOp == deleteStrategyOrphanAnnValue {
+			return DryRunResult{Type: DryRunResultSkipped, Reason: "delete strategy is orphan"}
+		}
+		return newDryRunResult(nil, nil, resources.Delete(c.change.ExistingResource()))
+
+	default:
+		return DryRunResult{Type: DryRunResultSkipped, Reason: "nothing to apply"}
+	}
+}
+
+// DryRunDefaultedFields returns paths of fields present in returned object
+// but not in sent object. Metadata and status are not considered
+// since they are always populated by the API server.
+func DryRunDefaultedFields(sent, returned map[string]interface{}) []string {
+	var result []string
+
+	for key, returnedVal := range returned {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		result = append(result, dryRunDefaultedFields(sent[key], returnedVal, "."+key)...)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func dryRunDefaultedFields(sent, returned interface{}, path string) []string {
+	var result []string
+
+	switch typedReturned := returned.(type) {
+	case map[string]interface{}:
+		typedSent, ok := sent.(map[string]interface{})
+		if !ok {
+			if sent == nil {
+				return []string{path}
+			}
+			return nil
+		}
+		for key, returnedVal := range typedReturned {
+			result = append(result, dryRunDefaultedFields(typedSent[key], returnedVal, path+"."+key)...)
+		}
+
+	case []interface{}:
+		typedSent, ok := sent.([]interface{})
+		if !ok {
+			if sent == nil {
+				return []string{path}
+			}
+			return nil
+		}
+		// Only compare lists item by item if they were not reshaped
+		if len(typedSent) == len(typedReturned) {
+			for i, returnedVal := range typedReturned {
+				result = append(result, dryRunDefaultedFields(typedSent[i], returnedVal, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+
+	default:
+		if sent == nil && returned != nil {
+			return []string{path}
+		}
+	}
+
+	return result
+}
+
+type dryRunSkipReasons struct {
+	newNamespaces map[string]struct{}
+	newGroupKinds map[schema.GroupKind]struct{}
+}
+
+// newDryRunSkipReasons finds namespaces and CRDs that will be created
+// by given changes, since resources that depend on them cannot be
+// validated via dry run until they exist
+func newDryRunSkipReasons(changes []*ClusterChange) dryRunSkipReasons {
+	reasons := dryRunSkipReasons{
+		newNamespaces: map[string]struct{}{},
+		newGroupKinds: map[schema.GroupKind]struct{}{},
+	}
+
+	for _, change := range changes {
+		if change.ApplyOp() != ClusterChangeApplyOpAdd {
+			continue
+		}
+
+		res := change.Resource()
+
+		if res.GroupKind() == (schema.GroupKind{Group: "", Kind: "Namespace"}) {
+			reasons.newNamespaces[res.Name()] = struct{}{}
+		}
+
+		if crd := ctlresm.NewAPIExtensionsVxCRD(res); crd != nil {
+			group, err := crd.Group()
+			if err != nil {
+				continue
+			}
+			kind, err := crd.Kind()
+			if err != nil {
+				continue
+			}
+			reasons.newGroupKinds[schema.GroupKind{Group: group, Kind: kind}] = struct{}{}
+		}
+	}
+
+	return reasons
+}
+
+func (r dryRunSkipReasons) For(change *ClusterChange) string {
+	res := change.Resource()
+
+	if _, found := r.newGroupKinds[res.GroupKind()]; found {
+		return "type is defined by CRD created in this deploy"
+	}
+	if _, found := r.newNamespaces[res.Namespace()]; found {
+		return "namespace is created in this deploy"
+	}
+	return ""
+}
@@ -0,0 +1,347 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	uierrs "github.com/cppforlife/go-cli-ui/errors"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	planAPIVersion = "kapp.k14s.io/v1alpha1"
+	planKind       = "DeployPlan"
+)
+
+// Plan is a serialized form of calculated change graph
+// that can be applied later (possibly by a different user)
+// as long as cluster state did not change since planning
+type Plan struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	App     PlanApp `json:"app"`
+	Summary string  `json:"summary"`
+
+	// Config holds kapp config resources that were used
+	// for planning (e.g. to keep same wait rules when applying)
+	Config []json.RawMessage `json:"config,omitempty"`
+
+	// Resources holds all app resources (not only changed ones)
+	// so that they could be recorded in app change when applying
+	Resources []json.RawMessage `json:"resources,omitempty"`
+
+	Changes []PlanChange `json:"changes"`
+}
+
+type PlanApp struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+}
+
+type PlanChange struct {
+	Description     string                       `json:"description"`
+	ApplyOp         ClusterChangeApplyOp         `json:"applyOp"`
+	ApplyStrategyOp ClusterChangeApplyStrategyOp `json:"applyStrategyOp,omitempty"`
+	WaitOp          ClusterChangeWaitOp          `json:"waitOp"`
+
+	// WaitingFor lists indexes of changes this change depends on
+	WaitingFor []int `json:"waitingFor,omitempty"`
+
+	NewResource               json.RawMessage `json:"newResource,omitempty"`
+	AppliedResource           json.RawMessage `json:"appliedResource,omitempty"`
+	ExistingResource          json.RawMessage `json:"existingResource,omitempty"`
+	ExistingResourceTransient bool            `json:"existingResourceTransient,omitempty"`
+
+	// ClusterResource identifies resource as it was
+	// found on the cluster at the time of planning
+	ClusterResource *PlanResourceRef `json:"clusterResource,omitempty"`
+}
+
+type PlanResourceRef struct {
+	APIVersion      string `json:"apiVersion"`
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+	UID             string `json:"uid"`
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+func NewPlan(app PlanApp, summary string, configRs, recordedRs []ctlres.Resource, changesGraph *ctldgraph.ChangeGraph) (Plan, error) {
+	plan := Plan{
+		APIVersion: planAPIVersion,
+		Kind:       planKind,
+		App:        app,
+		Summary:    summary,
+	}
+
+	for _, res := range configRs {
+		bs, err := res.AsCompactBytes()
+		if err != nil {
+			return Plan{}, fmt.Errorf("Serializing config resource '%s': %w", res.Description(), err)
+		}
+		plan.Config = append(plan.Config, bs)
+	}
+
+	for _, res := range recordedRs {
+		bs, err := res.AsCompactBytes()
+		if err != nil {
+			return Plan{}, fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+		}
+		plan.Resources = append(plan.Resources, bs)
+	}
+
+	graphChanges := changesGraph.All()
+	idxByChange := map[*ctldgraph.Change]int{}
+
+	for i, change := range graphChanges {
+		idxByChange[change] = i
+	}
+
+	for _, change := range graphChanges {
+		planChange, err := newPlanChange(change.Change.(wrappedClusterChange).ClusterChange)
+		if err != nil {
+			return Plan{}, err
+		}
+
+		for _, waitingForChange := range change.WaitingFor {
+			idx, found := idxByChange[waitingForChange]
+			if !found {
+				return Plan{}, fmt.Errorf("Expected change '%s' to depend on a known change", change.Description())
+			}
+			planChange.WaitingFor = append(planChange.WaitingFor, idx)
+		}
+
+		plan.Changes = append(plan.Changes, planChange)
+	}
+
+	return plan, nil
+}
+
+func newPlanChange(change *ClusterChange) (PlanChange, error) {
+	strategyOp, err := change.ApplyStrategyOp()
+	if err != nil {
+		return PlanChange{}, err
+	}
+
+	planChange := PlanChange{
+		Description:     change.ApplyDescription(),
+		ApplyOp:         change.ApplyOp(),
+		ApplyStrategyOp: strategyOp,
+		WaitOp:          change.WaitOp(),
+	}
+
+	resources := []struct {
+		Res ctlres.Resource
+		Out *json.RawMessage
+	}{
+		{change.change.NewResource(), &planChange.NewResource},
+		{change.change.AppliedResource(), &planChange.AppliedResource},
+		{change.change.ExistingResource(), &planChange.ExistingResource},
+	}
+
+	for _, item := range resources {
+		if item.Res == nil {
+			continue
+		}
+		bs, err := item.Res.AsCompactBytes()
+		if err != nil {
+			return PlanChange{}, fmt.Errorf("Serializing resource '%s': %w", item.Res.Description(), err)
+		}
+		*item.Out = bs
+	}
+
+	if existingRes := change.change.ExistingResource(); existingRes != nil {
+		planChange.ExistingResourceTransient = existingRes.Transient()
+	}
+
+	if clusterRes := change.change.ClusterOriginalResource(); clusterRes != nil {
+		planChange.ClusterResource = &PlanResourceRef{
+			APIVersion:      clusterRes.APIVersion(),
+			Kind:            clusterRes.Kind(),
+			Namespace:       clusterRes.Namespace(),
+			Name:            clusterRes.Name(),
+			UID:             clusterRes.UID(),
+			ResourceVersion: planResourceVersion(clusterRes),
+		}
+	}
+
+	return planChange, nil
+}
+
+func NewPlanFromBytes(bs []byte) (Plan, error) {
+	var plan Plan
+
+	err := json.Unmarshal(bs, &plan)
+	if err != nil {
+		return Plan{}, fmt.Errorf("Unmarshaling plan: %w", err)
+	}
+
+	if plan.APIVersion != planAPIVersion || plan.Kind != planKind {
+		return Plan{}, fmt.Errorf("Expected plan to have apiVersion '%s' and kind '%s', but was '%s' and '%s'",
+			planAPIVersion, planKind, plan.APIVersion, plan.Kind)
+	}
+
+	return plan, nil
+}
+
+func (p Plan) AsBytes() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+func (p Plan) ConfigResources() ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	for _, bs := range p.Config {
+		res, err := p.resource(bs)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing plan config: %w", err)
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// RecordedResources returns all app resources that were
+// included in the plan (nil for plans without resources)
+func (p Plan) RecordedResources() ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	for _, bs := range p.Resources {
+		res, err := p.resource(bs)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing plan resource: %w", err)
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// DiffChanges reconstructs planned changes against current cluster state.
+// Error is returned if any resource changed on the cluster since planning.
+func (p Plan) DiffChanges(identifiedResources ctlres.IdentifiedResources, opts ctldiff.ChangeOpts) ([]ctldiff.Change, error) {
+	var changes []ctldiff.Change
+	var driftedDescs []string
+
+	for _, planChange := range p.Changes {
+		newRes, err := p.resource(planChange.NewResource)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing plan change '%s': %w", planChange.Description, err)
+		}
+
+		appliedRes, err := p.resource(planChange.AppliedResource)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing plan change '%s': %w", planChange.Description, err)
+		}
+
+		existingRes, err := p.resource(planChange.ExistingResource)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing plan change '%s': %w", planChange.Description, err)
+		}
+
+		if existingRes == nil && newRes == nil {
+			return nil, fmt.Errorf("Expected plan change '%s' to have either new or existing resource", planChange.Description)
+		}
+
+		if existingRes != nil {
+			existingRes.MarkTransient(planChange.ExistingResourceTransient)
+		}
+
+		clusterRes, driftedDesc, err := p.clusterResource(planChange, newRes, existingRes, identifiedResources)
+		if err != nil {
+			return nil, err
+		}
+		if len(driftedDesc) > 0 {
+			driftedDescs = append(driftedDescs, driftedDesc)
+			continue
+		}
+
+		changes = append(changes, ctldiff.NewChange(existingRes, newRes, appliedRes, clusterRes, opts))
+	}
+
+	if len(driftedDescs) > 0 {
+		return nil, uierrs.NewSemiStructuredError(fmt.Errorf(
+			"Refusing to apply plan since cluster state changed after planning: [%s]", strings.Join(driftedDescs, ", ")))
+	}
+
+	return changes, nil
+}
+
+func (p Plan) clusterResource(planChange PlanChange, newRes, existingRes ctlres.Resource,
+	identifiedResources ctlres.IdentifiedResources) (ctlres.Resource, string, error) {
+
+	res := newRes
+	if existingRes != nil {
+		res = existingRes
+	}
+
+	// Resources with exists annotation are expected to be created by someone else
+	if planChange.ApplyOp == ClusterChangeApplyOpExists {
+		return nil, "", nil
+	}
+
+	clusterRes, found, err := identifiedResources.Exists(res, ctlres.ExistsOpts{})
+	if err != nil {
+		return nil, "", err
+	}
+
+	ref := planChange.ClusterResource
+
+	switch {
+	case ref == nil && !found:
+		return nil, "", nil
+
+	case ref == nil && found:
+		return nil, fmt.Sprintf("%s: was created", res.Description()), nil
+
+	case !found:
+		return nil, fmt.Sprintf("%s: was deleted", res.Description()), nil
+	}
+
+	currResourceVersion := planResourceVersion(clusterRes)
+
+	if clusterRes.UID() != ref.UID {
+		return nil, fmt.Sprintf("%s: was recreated (uid: planned '%s', current '%s')",
+			res.Description(), ref.UID, clusterRes.UID()), nil
+	}
+	if currResourceVersion != ref.ResourceVersion {
+		return nil, fmt.Sprintf("%s: was updated (resourceVersion: planned '%s', current '%s')",
+			res.Description(), ref.ResourceVersion, currResourceVersion), nil
+	}
+
+	err = ctlres.NewIdentityAnnotation(clusterRes).RemoveMod().Apply(clusterRes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return clusterRes, "", nil
+}
+
+func (p Plan) resource(bs json.RawMessage) (ctlres.Resource, error) {
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	res, err := ctlres.NewResourceFromBytes(bs)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res, nil
+}
+
+func planResourceVersion(res ctlres.Resource) string {
+	val, _, _ := unstructured.NestedString(res.UnstructuredObject(), "metadata", "resourceVersion")
+	return val
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"time"
+
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+)
+
+type ProgressEventType string
+
+const (
+	ProgressEventTypeApplyStart  ProgressEventType = "apply-start"
+	ProgressEventTypeApplyFinish ProgressEventType = "apply-finish"
+	ProgressEventTypeWaitState   ProgressEventType = "wait-state"
+	ProgressEventTypeBlocked     ProgressEventType = "blocked"
+	ProgressEventTypeOutcome     ProgressEventType = "outcome"
+
+	// Plain text messages are also available as events
+	ProgressEventTypeSection ProgressEventType = "section"
+	ProgressEventTypeMessage ProgressEventType = "message"
+)
+
+// ProgressEvent is a structured equivalent of progress messages
+// shown while applying and waiting for changes
+type ProgressEvent struct {
+	Type ProgressEventType `json:"type"`
+	Time time.Time         `json:"time"`
+
+	Change *ProgressEventChange `json:"change,omitempty"`
+
+	// State is one of ok, error, retryable (for apply events)
+	// or DoneApplyStateUI.State (for wait events)
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// BlockedBy lists resources that blocked change is waiting for
+	BlockedBy []string `json:"blockedBy,omitempty"`
+
+	// Successful is only set for outcome events
+	Successful *bool `json:"successful,omitempty"`
+}
+
+type ProgressEventChange struct {
+	Resource   string               `json:"resource"`
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Namespace  string               `json:"namespace,omitempty"`
+	Name       string               `json:"name"`
+	ApplyOp    ClusterChangeApplyOp `json:"applyOp"`
+	WaitOp     ClusterChangeWaitOp  `json:"waitOp"`
+}
+
+// ProgressUI is optionally implemented by UI to receive progress events
+type ProgressUI interface {
+	NotifyProgress(ProgressEvent)
+}
+
+func NewProgressEventChange(change *ClusterChange) *ProgressEventChange {
+	res := change.Resource()
+	return &ProgressEventChange{
+		Resource:   res.Description(),
+		APIVersion: res.APIVersion(),
+		Kind:       res.Kind(),
+		Namespace:  res.Namespace(),
+		Name:       res.Name(),
+		ApplyOp:    change.ApplyOp(),
+		WaitOp:     change.WaitOp(),
+	}
+}
+
+func notifyProgress(ui UI, event ProgressEvent) {
+	if progressUI, ok := ui.(ProgressUI); ok {
+		if event.Time.IsZero() {
+			event.Time = time.Now().UTC()
+		}
+		progressUI.NotifyProgress(event)
+	}
+}
+
+func notifyBlockedProgress(ui UI, blockedChanges *ctldgraph.BlockedChanges) {
+	for _, change := range blockedChanges.Blocked() {
+		var blockedBy []string
+		for _, childChange := range blockedChanges.BlockedBy(change) {
+			blockedBy = append(blockedBy, childChange.Change.Resource().Description())
+		}
+		notifyProgress(ui, ProgressEvent{
+			Type:      ProgressEventTypeBlocked,
+			Change:    NewProgressEventChange(change.Change.(wrappedClusterChange).ClusterChange),
+			BlockedBy: blockedBy,
+		})
+	}
+}
+
+// NewOutcomeProgressEvent returns event that ends progress events
+// of an operation (e.g. deploy) that succeeded or failed with given error
+func NewOutcomeProgressEvent(err error) ProgressEvent {
+	successful := err == nil
+	event := ProgressEvent{
+		Type:       ProgressEventTypeOutcome,
+		Time:       time.Now().UTC(),
+		State:      "ok",
+		Successful: &successful,
+	}
+	if err != nil {
+		event.State = "error"
+		event.Message = err.Error()
+	}
+	return event
+}
@@ -31,5 +31,5 @@ func (c ReconcilingChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, e
 		return ctlresm.DoneApplyState{}, nil, err
 	}
 
-	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated).IsDoneApplying()
+	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated, c.identifiedResources.ListAssociated).IsDoneApplying()
 }
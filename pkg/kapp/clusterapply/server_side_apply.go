@@ -0,0 +1,153 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultFieldManager = "kapp"
+)
+
+var (
+	// Matches messages such as 'conflict with "manager" using v1'
+	fieldOwnershipConflictManagerRegexp = regexp.MustCompile(`^conflict with "([^"]+)"`)
+)
+
+// FieldOwnershipConflictsStrategy is implemented by apply strategies
+// that may be rejected because fields are owned by other field managers
+type FieldOwnershipConflictsStrategy interface {
+	FieldOwnershipConflicts() (FieldOwnershipConflicts, error)
+}
+
+type FieldOwnershipConflict struct {
+	Manager string
+	Field   string
+	Message string
+}
+
+func (c FieldOwnershipConflict) String() string {
+	return fmt.Sprintf("%s (%s)", c.Field, c.Message)
+}
+
+type FieldOwnershipConflicts []FieldOwnershipConflict
+
+// NewFieldOwnershipConflictsFromErr returns conflicts found within
+// server-side apply error; nil is returned for other errors
+func NewFieldOwnershipConflictsFromErr(err error) FieldOwnershipConflicts {
+	if !apierrors.IsConflict(err) {
+		return nil
+	}
+
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) {
+		return nil
+	}
+
+	details := statusErr.Status().Details
+	if details == nil {
+		return nil
+	}
+
+	var result FieldOwnershipConflicts
+
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := FieldOwnershipConflict{Field: cause.Field, Message: cause.Message}
+		if match := fieldOwnershipConflictManagerRegexp.FindStringSubmatch(cause.Message); len(match) == 2 {
+			conflict.Manager = match[1]
+		}
+		result = append(result, conflict)
+	}
+
+	return result
+}
+
+// WithoutManager excludes conflicts with given field manager.
+// Such conflicts happen when resources were previously saved by kapp
+// via create or update operations using the same field manager name.
+func (c FieldOwnershipConflicts) WithoutManager(manager string) FieldOwnershipConflicts {
+	var result FieldOwnershipConflicts
+	for _, conflict := range c {
+		if conflict.Manager != manager {
+			result = append(result, conflict)
+		}
+	}
+	return result
+}
+
+func (c FieldOwnershipConflicts) Strings() []string {
+	var result []string
+	for _, conflict := range c {
+		result = append(result, conflict.String())
+	}
+	return result
+}
+
+func (c AddOrUpdateChange) serverSideApply() error {
+	appliedRes, err := c.identifiedResources.Apply(c.change.AppliedResource(), c.serverSideApplyOpts(c.opts.ForceConflicts))
+	if err != nil {
+		conflicts := NewFieldOwnershipConflictsFromErr(err)
+		if len(conflicts) == 0 {
+			return err
+		}
+
+		otherConflicts := conflicts.WithoutManager(c.fieldManager())
+		if len(otherConflicts) > 0 {
+			return fmt.Errorf("Field ownership conflicts (hint: use --apply-force-conflicts to take ownership):\n  %s\n%w",
+				strings.Join(otherConflicts.Strings(), "\n  "), err)
+		}
+
+		// Take over fields previously managed by kapp via non-apply operations
+		appliedRes, err = c.identifiedResources.Apply(c.change.AppliedResource(), c.serverSideApplyOpts(true))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.recordAppliedResource(appliedRes)
+}
+
+func (c AddOrUpdateChange) serverSideApplyConflicts() (FieldOwnershipConflicts, error) {
+	if c.change.ExistingResource() == nil {
+		return nil, nil
+	}
+
+	dryRunResources, err := c.identifiedResources.WithDryRun()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = dryRunResources.Apply(c.change.AppliedResource(), c.serverSideApplyOpts(false))
+	if err != nil {
+		conflicts := NewFieldOwnershipConflictsFromErr(err)
+		if conflicts == nil {
+			return nil, err
+		}
+		return conflicts.WithoutManager(c.fieldManager()), nil
+	}
+
+	return nil, nil
+}
+
+func (c AddOrUpdateChange) serverSideApplyOpts(force bool) ctlres.ApplyOpts {
+	return ctlres.ApplyOpts{FieldManager: c.fieldManager(), Force: force}
+}
+
+func (c AddOrUpdateChange) fieldManager() string {
+	if len(c.opts.FieldManager) > 0 {
+		return c.opts.FieldManager
+	}
+	return DefaultFieldManager
+}
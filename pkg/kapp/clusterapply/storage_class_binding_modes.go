@@ -0,0 +1,59 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"context"
+	"sync"
+
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StorageClassBindingModes finds volume binding modes of storage classes.
+// Since binding mode of a storage class is immutable, found modes are
+// cached so that each storage class is fetched at most once per deploy.
+type StorageClassBindingModes struct {
+	coreClient kubernetes.Interface
+
+	modes     map[string]storagev1.VolumeBindingMode
+	modesLock sync.Mutex
+}
+
+func NewStorageClassBindingModes(coreClient kubernetes.Interface) *StorageClassBindingModes {
+	return &StorageClassBindingModes{coreClient: coreClient, modes: map[string]storagev1.VolumeBindingMode{}}
+}
+
+// VolumeBindingMode returns binding mode of a storage class
+// (false is returned if storage class does not exist)
+func (m *StorageClassBindingModes) VolumeBindingMode(name string) (storagev1.VolumeBindingMode, bool, error) {
+	m.modesLock.Lock()
+	defer m.modesLock.Unlock()
+
+	if mode, found := m.modes[name]; found {
+		return mode, true, nil
+	}
+
+	sc, err := m.coreClient.StorageV1().StorageClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		// Missing storage class is not cached as it may be created later during deploy.
+		// Storage classes are cluster scoped, hence they may not be accessible
+		// to users with namespace scoped permissions (claims are expected to be bound then).
+		if errors.IsNotFound(err) || errors.IsForbidden(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	mode := storagev1.VolumeBindingImmediate
+	if sc.VolumeBindingMode != nil {
+		mode = *sc.VolumeBindingMode
+	}
+
+	m.modes[name] = mode
+
+	return mode, true, nil
+}
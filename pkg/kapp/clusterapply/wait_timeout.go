@@ -0,0 +1,59 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+const (
+	waitTimeoutAnnKey       = "kapp.k14s.io/wait-timeout"        // valid value is a duration (e.g. 20m; 0s means no timeout)
+	waitTimeoutActionAnnKey = "kapp.k14s.io/wait-timeout-action" // valid values are fail (default) and warn
+)
+
+// WaitTimeout specifies how long to wait for a resource
+// and what to do once resource times out
+type WaitTimeout struct {
+	Duration time.Duration // 0 means no timeout
+	Warn     bool
+}
+
+// WaitTimeout returns timeout for this change. Resource annotations
+// take precedence over wait rules, which take precedence over given default.
+func (c *ClusterChange) WaitTimeout(defaultDuration time.Duration) (WaitTimeout, error) {
+	res := c.Resource()
+	result := WaitTimeout{Duration: defaultDuration}
+
+	timeout, timeoutAction := c.convergedResFactory.waitRules.Timeout(res)
+
+	if val, found := res.Annotations()[waitTimeoutAnnKey]; found {
+		timeout = val
+	}
+	if val, found := res.Annotations()[waitTimeoutActionAnnKey]; found {
+		timeoutAction = val
+	}
+
+	if len(timeout) > 0 {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return WaitTimeout{}, fmt.Errorf("Expected wait timeout '%s' on resource '%s' to be a duration: %w",
+				timeout, res.Description(), err)
+		}
+		result.Duration = duration
+	}
+
+	switch timeoutAction {
+	case "", ctlconf.WaitRuleTimeoutActionFail:
+	case ctlconf.WaitRuleTimeoutActionWarn:
+		result.Warn = true
+	default:
+		return WaitTimeout{}, fmt.Errorf("Expected wait timeout action on resource '%s' to be one of: %s, %s (given: '%s')",
+			res.Description(), ctlconf.WaitRuleTimeoutActionFail, ctlconf.WaitRuleTimeoutActionWarn, timeoutAction)
+	}
+
+	return result, nil
+}
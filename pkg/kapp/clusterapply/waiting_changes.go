@@ -9,6 +9,7 @@ import (
 	"time"
 
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
@@ -19,6 +20,11 @@ type WaitingChangesOpts struct {
 	ResourceTimeout time.Duration
 	CheckInterval   time.Duration
 	Concurrency     int
+
+	// Watch resources to re-evaluate changes as soon as they are updated
+	// (polling every CheckInterval is used when watching is not possible)
+	Watch               bool
+	WatchResyncInterval time.Duration
 }
 
 type WaitingChanges struct {
@@ -28,6 +34,20 @@ type WaitingChanges struct {
 	opts           WaitingChangesOpts
 	ui             UI
 	exitOnError    bool
+
+	watcher        *WaitingChangesWatcher
+	updatedChanges map[*ClusterChange]struct{}
+	resyncChanges  bool
+
+	// Last notified state used to only report state transitions
+	lastStates map[*ClusterChange]DoneApplyStateUI
+
+	timeouts map[*ClusterChange]waitTimeoutResult
+}
+
+type waitTimeoutResult struct {
+	WaitTimeout
+	Err error
 }
 
 type WaitingChange struct {
@@ -36,12 +56,28 @@ type WaitingChange struct {
 	startTime time.Time
 }
 
-func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool) *WaitingChanges {
-	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError}
+func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, resourcesWatcher ResourcesWatcher, ui UI, exitOnError bool) *WaitingChanges {
+	if !opts.Watch {
+		resourcesWatcher = nil
+	}
+	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError,
+		NewWaitingChangesWatcher(resourcesWatcher, ui), map[*ClusterChange]struct{}{}, false,
+		map[*ClusterChange]DoneApplyStateUI{}, map[*ClusterChange]waitTimeoutResult{}}
 }
 
 func (c *WaitingChanges) Track(changes []WaitingChange) {
 	c.trackedChanges = append(c.trackedChanges, changes...)
+
+	// Start watching before checking on newly tracked changes
+	// so that updates happening in between are not missed
+	c.watcher.Track(changes)
+
+	for _, change := range changes {
+		c.updatedChanges[change.Cluster] = struct{}{}
+
+		timeout, err := change.Cluster.WaitTimeout(c.opts.ResourceTimeout)
+		c.timeouts[change.Cluster] = waitTimeoutResult{timeout, err}
+	}
 }
 
 func (c *WaitingChanges) IsEmpty() bool {
@@ -53,6 +89,9 @@ type waitResult struct {
 	State    ctlresm.DoneApplyState
 	DescMsgs []string
 	Err      error
+
+	// Resource timed out, but timeout is only considered a warning
+	TimedOutWithWarning bool
 }
 
 func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
@@ -61,38 +100,60 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 	for {
 		c.ui.NotifySection("waiting on %d changes %s", len(c.trackedChanges), c.stats())
 
-		waitCh := make(chan waitResult, len(c.trackedChanges))
+		changesToCheck, newInProgressChanges := c.changesToCheck()
+
+		waitCh := make(chan waitResult, len(changesToCheck))
 		waitThrottle := util.NewThrottle(c.opts.Concurrency)
 
-		for _, change := range c.trackedChanges {
+		for _, change := range changesToCheck {
 			change := change // copy
 
 			go func() {
 				waitThrottle.Take()
 				defer waitThrottle.Done()
 
-				state, descMsgs, err := change.Cluster.IsDoneApplying()
+				var state ctlresm.DoneApplyState
+				var descMsgs []string
+				var timedOutWithWarning bool
+
+				timeout := c.timeouts[change.Cluster]
+				err := timeout.Err
+				if err == nil {
+					state, descMsgs, err = change.Cluster.IsDoneApplying()
+				}
 				// check for resource timeout
 				if err == nil {
-					if c.opts.ResourceTimeout != 0 && time.Now().Sub(change.startTime) > c.opts.ResourceTimeout {
-						err = fmt.Errorf("Resource timed out waiting after %s", c.opts.ResourceTimeout)
+					if c.isResourceTimedOut(change) {
+						if !timeout.Warn {
+							err = fmt.Errorf("Resource timed out waiting after %s", timeout.Duration)
+						} else if !state.Done {
+							timedOutWithWarning = true
+							state = ctlresm.DoneApplyState{Done: true, Successful: false,
+								Message: fmt.Sprintf("Resource timed out waiting after %s", timeout.Duration)}
+						}
 					}
 				}
-				waitCh <- waitResult{Change: change, State: state, DescMsgs: descMsgs, Err: err}
+				waitCh <- waitResult{Change: change, State: state, DescMsgs: descMsgs, Err: err,
+					TimedOutWithWarning: timedOutWithWarning}
 			}()
 		}
 
-		var newInProgressChanges []WaitingChange
 		var doneChanges []WaitingChange
 		var unsuccessfulChangeDesc []string
 
-		for i := 0; i < len(c.trackedChanges); i++ {
+		for i := 0; i < len(changesToCheck); i++ {
 			result := <-waitCh
 			change, state, descMsgs, err := result.Change, result.State, result.DescMsgs, result.Err
 
 			desc := fmt.Sprintf("waiting on %s", change.Cluster.WaitDescription())
 			c.ui.Notify(descMsgs)
 
+			stateUI := NewDoneApplyStateUI(state, err)
+			if result.TimedOutWithWarning {
+				stateUI.State = "warn"
+			}
+			c.notifyWaitState(change, stateUI)
+
 			if err != nil {
 				err = fmt.Errorf("%s: Errored: %w", desc, err)
 				if c.exitOnError {
@@ -105,6 +166,14 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 				c.numWaited++
 			}
 
+			// Continue with dependent changes as if resource was successfully waited on
+			if result.TimedOutWithWarning {
+				c.ui.Notify([]string{fmt.Sprintf("Warning: %s: %s (continuing since timeout action is %s)",
+					desc, state.Message, ctlconf.WaitRuleTimeoutActionWarn)})
+				doneChanges = append(doneChanges, change)
+				continue
+			}
+
 			switch {
 			case !state.Done:
 				newInProgressChanges = append(newInProgressChanges, change)
@@ -135,7 +204,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			return doneChanges, unsuccessfulChangeDesc, nil
 		}
 
-		if time.Now().Sub(startTime) > c.opts.Timeout {
+		if c.isTimedOut(startTime) {
 			var trackedResourcesDesc []string
 			for _, change := range c.trackedChanges {
 				trackedResourcesDesc = append(trackedResourcesDesc, change.Cluster.Resource().Description())
@@ -143,8 +212,97 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			return nil, unsuccessfulChangeDesc, uierrs.NewSemiStructuredError(fmt.Errorf("Timed out waiting after %s for resources: [%s]", c.opts.Timeout, strings.Join(trackedResourcesDesc, ", ")))
 		}
 
+		c.waitForUpdates(startTime)
+	}
+}
+
+// changesToCheck returns changes that need to be checked on
+// and remaining changes that are known to be unchanged since last check
+func (c *WaitingChanges) changesToCheck() ([]WaitingChange, []WaitingChange) {
+	if !c.watcher.IsWatching() || c.resyncChanges {
+		c.resyncChanges = false
+		c.updatedChanges = map[*ClusterChange]struct{}{}
+		return c.trackedChanges, nil
+	}
+
+	var changesToCheck, unchangedChanges []WaitingChange
+
+	for _, change := range c.trackedChanges {
+		_, updated := c.updatedChanges[change.Cluster]
+		if updated || c.isResourceTimedOut(change) {
+			changesToCheck = append(changesToCheck, change)
+		} else {
+			unchangedChanges = append(unchangedChanges, change)
+		}
+	}
+
+	c.updatedChanges = map[*ClusterChange]struct{}{}
+
+	return changesToCheck, unchangedChanges
+}
+
+func (c *WaitingChanges) waitForUpdates(startTime time.Time) {
+	if !c.watcher.IsWatching() {
 		time.Sleep(c.opts.CheckInterval)
+		return
+	}
+
+	// Wake up periodically to pick up any missed updates
+	// and to notice timeouts in a timely manner
+	timeout := c.opts.WatchResyncInterval
+	if timeout <= 0 {
+		timeout = c.opts.CheckInterval
+	}
+	if remaining := c.opts.Timeout - time.Now().Sub(startTime); remaining > 0 && remaining < timeout {
+		timeout = remaining
 	}
+	for _, change := range c.trackedChanges {
+		if resTimeout := c.timeouts[change.Cluster].Duration; resTimeout != 0 {
+			if remaining := resTimeout - time.Now().Sub(change.startTime); remaining < timeout {
+				timeout = remaining
+			}
+		}
+	}
+	if timeout < c.opts.CheckInterval {
+		timeout = c.opts.CheckInterval
+	}
+
+	c.updatedChanges, c.resyncChanges = c.watcher.Wait(c.trackedChanges, timeout)
+}
+
+func (c *WaitingChanges) notifyWaitState(change WaitingChange, stateUI DoneApplyStateUI) {
+	if lastStateUI, found := c.lastStates[change.Cluster]; found && lastStateUI == stateUI {
+		return
+	}
+	c.lastStates[change.Cluster] = stateUI
+
+	notifyProgress(c.ui, ProgressEvent{
+		Type:    ProgressEventTypeWaitState,
+		Change:  NewProgressEventChange(change.Cluster),
+		State:   stateUI.State,
+		Message: stateUI.Message,
+	})
+}
+
+// isTimedOut returns true once overall wait timeout is reached. Deadline is
+// extended for tracked changes with larger resource timeouts so that
+// they are waited on for as long as their resource timeout specifies.
+func (c *WaitingChanges) isTimedOut(startTime time.Time) bool {
+	if time.Now().Sub(startTime) <= c.opts.Timeout {
+		return false
+	}
+	for _, change := range c.trackedChanges {
+		resTimeout := c.timeouts[change.Cluster].Duration
+		if resTimeout > c.opts.Timeout && !c.isResourceTimedOut(change) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *WaitingChanges) isResourceTimedOut(change WaitingChange) bool {
+	resTimeout := c.timeouts[change.Cluster].Duration
+	return resTimeout != 0 && time.Now().Sub(change.startTime) > resTimeout
 }
 
 func (c *WaitingChanges) Complete() error {
@@ -152,6 +310,11 @@ func (c *WaitingChanges) Complete() error {
 	return nil
 }
 
+// Stop stops watching resources
+func (c *WaitingChanges) Stop() {
+	c.watcher.Stop()
+}
+
 func (c *WaitingChanges) stats() string {
 	return fmt.Sprintf("[%d/%d done]", c.numWaited, c.numTotal)
 }
@@ -0,0 +1,182 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"time"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Give related events (e.g. Deployment, ReplicaSet and Pod updates)
+	// a chance to arrive together before evaluating changes
+	waitWatchCoalesceInterval = 500 * time.Millisecond
+)
+
+type ResourcesWatcher interface {
+	Watch(opts ctlres.WatchOpts, resourcesCh chan<- ctlres.Resource, cancelCh chan struct{}) error
+}
+
+var _ ResourcesWatcher = ctlres.IdentifiedResources{}
+
+// WaitingChangesWatcher watches GroupKinds (and associated resource types)
+// of changes being waited on so that changes could be re-evaluated
+// as soon as their resources are updated instead of periodically.
+// Once watching fails (e.g. watch is forbidden) it stays disabled
+// and callers are expected to fallback to polling.
+type WaitingChangesWatcher struct {
+	watcher ResourcesWatcher
+	ui      UI
+
+	resourcesCh chan ctlres.Resource
+	cancelCh    chan struct{}
+
+	watchedKeys map[waitWatchKey]struct{}
+	disabled    bool
+}
+
+// waitWatchKey identifies watch shared by all changes
+// that wait on resources of the same type in the same namespace
+// (changes' names and label selectors are matched client side)
+type waitWatchKey struct {
+	GroupKind   schema.GroupKind
+	ResourceRef ctlres.ResourceRef
+	Namespace   string
+}
+
+func NewWaitingChangesWatcher(watcher ResourcesWatcher, ui UI) *WaitingChangesWatcher {
+	return &WaitingChangesWatcher{
+		watcher:     watcher,
+		ui:          ui,
+		resourcesCh: make(chan ctlres.Resource),
+		cancelCh:    make(chan struct{}),
+		watchedKeys: map[waitWatchKey]struct{}{},
+		disabled:    watcher == nil,
+	}
+}
+
+func (w *WaitingChangesWatcher) IsWatching() bool { return !w.disabled }
+
+// Track starts watching resources of given changes
+// unless they are already being watched. Resources are watched
+// once per GroupKind (or resource ref) and namespace.
+func (w *WaitingChangesWatcher) Track(changes []WaitingChange) {
+	if w.disabled {
+		return
+	}
+
+	newOptsByNs := map[string]*ctlres.WatchOpts{}
+	var namespaces []string
+
+	for _, change := range changes {
+		for _, opts := range change.Cluster.WaitWatchOpts() {
+			nsNames := opts.Namespaces
+			if len(nsNames) == 0 {
+				nsNames = []string{""} // all namespaces
+			}
+
+			for _, ns := range nsNames {
+				newOpts, found := newOptsByNs[ns]
+				if !found {
+					newOpts = &ctlres.WatchOpts{}
+					if len(ns) > 0 {
+						newOpts.Namespaces = []string{ns}
+					}
+					newOptsByNs[ns] = newOpts
+					namespaces = append(namespaces, ns)
+				}
+
+				for _, gk := range opts.GroupKinds {
+					key := waitWatchKey{GroupKind: gk, Namespace: ns}
+					if _, found := w.watchedKeys[key]; !found {
+						w.watchedKeys[key] = struct{}{}
+						newOpts.GroupKinds = append(newOpts.GroupKinds, gk)
+					}
+				}
+
+				for _, ref := range opts.ResourceRefs {
+					key := waitWatchKey{ResourceRef: ref, Namespace: ns}
+					if _, found := w.watchedKeys[key]; !found {
+						w.watchedKeys[key] = struct{}{}
+						newOpts.ResourceRefs = append(newOpts.ResourceRefs, ref)
+					}
+				}
+			}
+		}
+	}
+
+	for _, ns := range namespaces {
+		newOpts := newOptsByNs[ns]
+		if len(newOpts.GroupKinds) == 0 && len(newOpts.ResourceRefs) == 0 {
+			continue
+		}
+
+		err := w.watcher.Watch(*newOpts, w.resourcesCh, w.cancelCh)
+		if err != nil {
+			w.ui.Notify([]string{fmt.Sprintf("Falling back to polling while waiting: %s", err)})
+			w.Stop()
+			return
+		}
+	}
+}
+
+// Wait blocks until resources of some of the given changes are updated
+// or until timeout. Returns changes that should be re-evaluated;
+// true is returned if timeout was reached.
+func (w *WaitingChangesWatcher) Wait(changes []WaitingChange, timeout time.Duration) (map[*ClusterChange]struct{}, bool) {
+	changesByAssociation := map[string][]*ClusterChange{}
+	optsByChange := map[*ClusterChange][]ctlres.WatchOpts{}
+
+	for _, change := range changes {
+		associationKey := ctlres.NewAssociationLabel(change.Cluster.Resource()).Value()
+		changesByAssociation[associationKey] = append(changesByAssociation[associationKey], change.Cluster)
+		optsByChange[change.Cluster] = change.Cluster.WaitWatchOpts()
+	}
+
+	timeoutCh := time.After(timeout)
+	var coalesceCh <-chan time.Time
+
+	updatedChanges := map[*ClusterChange]struct{}{}
+
+	for {
+		select {
+		case res := <-w.resourcesCh:
+			if val, found := res.Labels()[ctlres.NewAssociationLabel(res).Key()]; found {
+				for _, change := range changesByAssociation[val] {
+					updatedChanges[change] = struct{}{}
+				}
+			}
+
+			// Changed resource itself and resources found by
+			// wait rules queries are matched against watch scope
+			for change, changeOpts := range optsByChange {
+				for _, opts := range changeOpts {
+					if opts.Matches(res) {
+						updatedChanges[change] = struct{}{}
+					}
+				}
+			}
+
+			if len(updatedChanges) > 0 && coalesceCh == nil {
+				coalesceCh = time.After(waitWatchCoalesceInterval)
+			}
+
+		case <-coalesceCh:
+			return updatedChanges, false
+
+		case <-timeoutCh:
+			return updatedChanges, true
+		}
+	}
+}
+
+func (w *WaitingChangesWatcher) Stop() {
+	if !w.disabled {
+		w.disabled = true
+		close(w.cancelCh)
+	}
+}
@@ -17,6 +17,14 @@ var (
 			ApplyIgnored: false,
 			Wait:         true,
 			WaitIgnored:  false,
+			AddOrUpdateChangeOpts: ctlcap.AddOrUpdateChangeOpts{
+				FieldManager: ctlcap.DefaultFieldManager,
+			},
+		},
+		ClusterChangeSetOpts: ctlcap.ClusterChangeSetOpts{
+			WaitingChangesOpts: ctlcap.WaitingChangesOpts{
+				Watch: true,
+			},
 		},
 	}
 	ApplyFlagsDeleteDefaults = ApplyFlags{
@@ -24,6 +32,14 @@ var (
 			ApplyIgnored: false,
 			Wait:         true,
 			WaitIgnored:  true,
+			AddOrUpdateChangeOpts: ctlcap.AddOrUpdateChangeOpts{
+				FieldManager: ctlcap.DefaultFieldManager,
+			},
+		},
+		ClusterChangeSetOpts: ctlcap.ClusterChangeSetOpts{
+			WaitingChangesOpts: ctlcap.WaitingChangesOpts{
+				Watch: true,
+			},
 		},
 	}
 )
@@ -49,6 +65,10 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 
 	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.DefaultUpdateStrategy, prefix+"apply-default-update-strategy",
 		defaults.AddOrUpdateChangeOpts.DefaultUpdateStrategy, "Change default update strategy")
+	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.FieldManager, prefix+"apply-field-manager",
+		defaults.AddOrUpdateChangeOpts.FieldManager, "Set field manager used by server-side-apply update strategy")
+	cmd.Flags().BoolVar(&s.AddOrUpdateChangeOpts.ForceConflicts, prefix+"apply-force-conflicts",
+		false, "Take ownership of conflicting fields when using server-side-apply update strategy")
 
 	cmd.Flags().BoolVar(&s.ExitEarlyOnApplyError, prefix+"exit-early-on-apply-error", true, "Exit quickly on apply failure")
 
@@ -56,13 +76,17 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 	cmd.Flags().BoolVar(&s.WaitIgnored, prefix+"wait-ignored", defaults.WaitIgnored, "Set to wait for ignored changes to be applied")
 
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.Timeout, prefix+"wait-timeout",
-		mustParseDuration("15m"), "Maximum amount of time to wait in wait phase")
+		mustParseDuration("15m"), "Maximum amount of time to wait in wait phase (extended for resources with larger resource timeout)")
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.ResourceTimeout, prefix+"wait-resource-timeout",
-		mustParseDuration("0s"), "Maximum amount of time to wait for a resource in wait phase (0s means no timeout)")
+		mustParseDuration("0s"), "Maximum amount of time to wait for a resource in wait phase (0s means no timeout; can be overridden via resource annotation or wait rule)")
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.CheckInterval, prefix+"wait-check-interval",
 		mustParseDuration("3s"), "Amount of time to sleep between checks while waiting")
 	cmd.Flags().IntVar(&s.WaitingChangesOpts.Concurrency, prefix+"wait-concurrency",
 		5, "Maximum number of concurrent wait operations")
+	cmd.Flags().BoolVar(&s.WaitingChangesOpts.Watch, prefix+"wait-watch",
+		defaults.WaitingChangesOpts.Watch, "Watch resources to check on them as soon as they change (falls back to polling if watching is not permitted)")
+	cmd.Flags().DurationVar(&s.WaitingChangesOpts.WatchResyncInterval, prefix+"wait-watch-resync-interval",
+		mustParseDuration("30s"), "Amount of time after which all resources are checked on while watching")
 
 	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false, "Return specific exit status based on number of changes")
 
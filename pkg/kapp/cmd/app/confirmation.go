@@ -0,0 +1,145 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+)
+
+type ConfirmationFlags struct {
+	AllowDangerousChanges []string
+}
+
+func (s *ConfirmationFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.AllowDangerousChanges, "allow-dangerous-change", nil,
+		"Allow changes matching confirmation rule without typed confirmation (could be specified multiple times)")
+}
+
+// DangerousChangesConfirmation asks to explicitly confirm changes
+// that match confirmation rules (in addition to regular confirmation)
+type DangerousChangesConfirmation struct {
+	rules   []ctlconf.ConfirmationRule
+	allowed []string
+	ui      ui.UI
+}
+
+type dangerousChanges struct {
+	Rule    ctlconf.ConfirmationRule
+	Changes []ctlcap.ChangeView
+}
+
+func NewDangerousChangesConfirmation(rules []ctlconf.ConfirmationRule,
+	allowed []string, ui ui.UI) DangerousChangesConfirmation {
+
+	return DangerousChangesConfirmation{rules, allowed, ui}
+}
+
+func (c DangerousChangesConfirmation) Confirm(graph *ctldgraph.ChangeGraph) error {
+	err := c.validateAllowed()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, dangerous := range c.dangerousChanges(graph) {
+		if c.isAllowed(dangerous.Rule) {
+			c.ui.PrintLinef("Allowed %d dangerous change(s) matching confirmation rule '%s'",
+				len(dangerous.Changes), dangerous.Rule.Name)
+			continue
+		}
+
+		if !c.ui.IsInteractive() {
+			errs = append(errs, fmt.Errorf("Expected --allow-dangerous-change=%s to be specified "+
+				"to apply %d change(s) matching confirmation rule '%s' in non-interactive mode",
+				dangerous.Rule.Name, len(dangerous.Changes), dangerous.Rule.Name))
+			continue
+		}
+
+		err := c.ask(dangerous)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c DangerousChangesConfirmation) ask(dangerous dangerousChanges) error {
+	c.ui.PrintLinef("")
+	c.ui.PrintLinef("Following changes match confirmation rule '%s':", dangerous.Rule.Name)
+
+	for _, change := range dangerous.Changes {
+		c.ui.PrintLinef("  - %s %s", ctlcap.ApplyOpCodeUI(change.ApplyOp()), change.Resource().Description())
+	}
+
+	text, err := c.ui.AskForText(fmt.Sprintf("Type '%s' to confirm", dangerous.Rule.Name))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(text) != dangerous.Rule.Name {
+		return fmt.Errorf("Stopped: Changes matching confirmation rule '%s' were not confirmed", dangerous.Rule.Name)
+	}
+
+	return nil
+}
+
+func (c DangerousChangesConfirmation) dangerousChanges(graph *ctldgraph.ChangeGraph) []dangerousChanges {
+	var result []dangerousChanges
+
+	for _, rule := range c.rules {
+		dangerous := dangerousChanges{Rule: rule}
+
+		for _, change := range graph.All() {
+			view, ok := change.Change.(ctlcap.ChangeView)
+			if !ok {
+				continue
+			}
+			if rule.Matches(view.Resource(), ctlcap.ApplyOpCodeUI(view.ApplyOp())) {
+				dangerous.Changes = append(dangerous.Changes, view)
+			}
+		}
+
+		if len(dangerous.Changes) > 0 {
+			result = append(result, dangerous)
+		}
+	}
+
+	return result
+}
+
+func (c DangerousChangesConfirmation) validateAllowed() error {
+	for _, name := range c.allowed {
+		var found bool
+		for _, rule := range c.rules {
+			if rule.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Expected --allow-dangerous-change to reference "+
+				"one of confirmation rules (unknown: '%s')", name)
+		}
+	}
+	return nil
+}
+
+func (c DangerousChangesConfirmation) isAllowed(rule ctlconf.ConfirmationRule) bool {
+	for _, name := range c.allowed {
+		if name == rule.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -27,8 +27,16 @@ type DeleteOptions struct {
 	DiffFlags           cmdtools.DiffFlags
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ApplyFlags          ApplyFlags
+	ConfirmationFlags   ConfirmationFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	PrevAppFlags        PrevAppFlags
+
+	// ConfigFiles provide kapp config (e.g. confirmation rules)
+	// instead of config recorded in last successful app change
+	ConfigFiles []string
+
+	// Set when UI is following delete progress
+	diffUIProgressEvents *ctldiffui.ProgressEvents
 }
 
 type changesSummary struct {
@@ -54,14 +62,27 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.DiffFlags.SetWithPrefix("diff", cmd)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeleteDefaults, cmd)
+	o.ConfirmationFlags.Set(cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
+	cmd.Flags().StringSliceVarP(&o.ConfigFiles, "file", "f", nil,
+		"Set file with kapp config, e.g. confirmation rules, instead of config recorded in last successful app change; "+
+			"other resources are ignored (format: /tmp/foo, https://..., -) (can repeat)")
 	return cmd
 }
 
 func (o *DeleteOptions) Run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	err := o.DiffFlags.ChangeSetViewOpts.Validate()
+	if err != nil {
+		return err
+	}
+
+	if o.DiffFlags.UILive {
+		o.diffUIProgressEvents = ctldiffui.NewProgressEvents()
+	}
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -109,7 +130,7 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
-	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	conf, err := o.conf(app)
 	if err != nil {
 		return err
 	}
@@ -118,7 +139,7 @@ func (o *DeleteOptions) Run() error {
 		o.calculateAndPresentChanges(existingResources, conf, supportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
-			return o.presentDiffUI(clusterChangesGraph)
+			return o.presentDiffUI(clusterChangesGraph, conf)
 		}
 		return err
 	}
@@ -134,7 +155,7 @@ func (o *DeleteOptions) Run() error {
 	}
 
 	if o.DiffFlags.UI {
-		return o.presentDiffUI(clusterChangesGraph)
+		return o.presentDiffUI(clusterChangesGraph, conf)
 	}
 
 	if o.DiffFlags.Run {
@@ -144,6 +165,19 @@ func (o *DeleteOptions) Run() error {
 		return nil
 	}
 
+	if o.diffUIProgressEvents != nil {
+		err = o.startDiffUI(clusterChangesGraph, conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = NewDangerousChangesConfirmation(conf.ConfirmationRules(),
+		o.ConfirmationFlags.AllowDangerousChanges, o.ui).Confirm(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
 	err = o.ui.AskForConfirmation()
 	if err != nil {
 		return err
@@ -254,7 +288,10 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		}
 
 		{ // Build cluster changes based on diff changes
-			msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+			var msgsUI ctlcap.UI = cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+			if o.diffUIProgressEvents != nil {
+				msgsUI = ctldiffui.NewProgressUI(msgsUI, o.diffUIProgressEvents)
+			}
 
 			convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
 				IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
@@ -278,13 +315,44 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
 		changeSetView := ctlcap.NewChangeSetView(
-			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
+			changeViews, conf.DiffMaskRules(), conf.DiffListMergeKeyRules(), o.DiffFlags.ChangeSetViewOpts)
 		changeSetView.Print(o.ui)
 	}
 
+	if len(o.DiffFlags.HTMLReport) > 0 {
+		report := ctldiffui.NewHTMLReport(ctlcap.ClusterChangesAsChangeViews(clusterChanges),
+			clusterChangesGraph, conf.DiffMaskRules(), o.DiffFlags.TextDiffViewOpts)
+
+		err := report.WriteToFile(o.DiffFlags.HTMLReport)
+		if err != nil {
+			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+		}
+	}
+
 	return clusterChangeSet, clusterChangesGraph, changesSummary{HasNoChanges: len(clusterChanges) == 0, SkippedChanges: skippedChanges}, nil
 }
 
+// conf returns config provided via files or otherwise config recorded
+// in last successful app change so that confirmation rules used
+// during deploy are also enforced without explicitly providing them
+func (o *DeleteOptions) conf(app ctlapp.App) (ctlconf.Conf, error) {
+	var configRs []ctlres.Resource
+	var err error
+
+	if len(o.ConfigFiles) > 0 {
+		configRs, err = configResourcesFromFiles(o.ConfigFiles)
+	} else {
+		configRs, err = lastSuccessfulChangeConfig(app)
+	}
+	if err != nil {
+		return ctlconf.Conf{}, err
+	}
+
+	// Resources other than kapp config are ignored
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(configRs)
+	return conf, err
+}
+
 const (
 	ownedForDeletionAnnKey = "kapp.k14s.io/owned-for-deletion" // valid values: ''
 )
@@ -300,9 +368,21 @@ func (o *DeleteOptions) changeIgnored(resources []ctlres.Resource) {
 	}
 }
 
-func (o *DeleteOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
+func (o *DeleteOptions) presentDiffUI(graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
 	opts := ctldiffui.ServerOpts{
-		DiffDataFunc: func() *ctldgraph.ChangeGraph { return graph },
+		DiffDataFunc:     func() *ctldgraph.ChangeGraph { return graph },
+		MaskRules:        conf.DiffMaskRules(),
+		TextDiffViewOpts: o.DiffFlags.TextDiffViewOpts,
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+func (o *DeleteOptions) startDiffUI(graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	opts := ctldiffui.ServerOpts{
+		DiffDataFunc:     func() *ctldgraph.ChangeGraph { return graph },
+		MaskRules:        conf.DiffMaskRules(),
+		TextDiffViewOpts: o.DiffFlags.TextDiffViewOpts,
+		ProgressEvents:   o.diffUIProgressEvents,
+	}
+	return ctldiffui.NewServer(opts, o.ui).Start()
+}
@@ -5,6 +5,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -49,12 +50,28 @@ type DeployOptions struct {
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ApplyFlags          ApplyFlags
 	DeployFlags         DeployFlags
+	ConfirmationFlags   ConfirmationFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	LabelFlags          LabelFlags
 
 	PreflightChecks *preflight.Registry
 
 	FileSystem fs.FS
+
+	// ResourcesFunc (if specified) provides resources instead of files
+	ResourcesFunc func() ([]ctlres.Resource, error)
+
+	// Set when rolling back after failed deploy
+	isRollback bool
+
+	// Set once changes are calculated (used by watch mode)
+	changeSummary string
+
+	// Set when UI is following apply progress
+	diffUIProgressEvents *ctldiffui.ProgressEvents
+
+	// Set when progress events are written as JSONL
+	jsonlProgressUI *JSONLProgressUI
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *DeployOptions {
@@ -78,6 +95,9 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
   # Deploy app 'app1' while showing full text diff
   kapp deploy -a app1 -f config/ --diff-changes
 
+  # Deploy app 'app1' again whenever files in config/ change
+  kapp deploy -a app1 -f config/ --watch --yes
+
   # Deploy app 'app1' based on remote file
   kapp deploy -a app1 \
     -f https://github.com/...download/v0.6.0/crds.yaml \
@@ -92,6 +112,7 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeployDefaults, cmd)
 	o.DeployFlags.Set(cmd)
+	o.ConfirmationFlags.Set(cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.LabelFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
@@ -101,8 +122,67 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeployOptions) Run() error {
+	jsonlProgress, err := o.DeployFlags.JSONLProgress()
+	if err != nil {
+		return err
+	}
+
+	// Progress file is opened once (watch cycles and rollback reuse it)
+	if jsonlProgress && o.jsonlProgressUI == nil {
+		progressFile, err := os.Create(o.DeployFlags.ProgressFile)
+		if err != nil {
+			return fmt.Errorf("Creating progress file: %w", err)
+		}
+		defer progressFile.Close()
+
+		o.jsonlProgressUI = NewJSONLProgressUI(progressFile)
+	}
+
+	err = o.run()
+
+	// Each watch cycle reports its own outcome and
+	// rollback is reported as part of the failed deploy
+	if !o.DeployFlags.Watch && !o.isRollback {
+		o.notifyOutcome(err)
+	}
+
+	return err
+}
+
+func (o *DeployOptions) run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	serverDryRun, err := o.DeployFlags.ServerDryRun()
+	if err != nil {
+		return err
+	}
+
+	err = o.DiffFlags.ChangeSetViewOpts.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Progress events are recorded from the start so that
+	// UI could be started after changes are calculated
+	if o.DiffFlags.UILive {
+		if o.DeployFlags.Watch {
+			return fmt.Errorf("Expected --diff-ui-live-alpha to not be used together with --watch")
+		}
+		o.diffUIProgressEvents = ctldiffui.NewProgressEvents()
+	}
+
+	if o.DeployFlags.Watch {
+		return o.runWatch()
+	}
+
+	if len(o.DeployFlags.PlanInput) > 0 {
+		return o.runPlan(serverDryRun)
+	}
+
+	if len(o.DeployFlags.PlanOutput) > 0 && !o.DiffFlags.Run {
+		return fmt.Errorf("Expected --plan-output to be used together with --diff-run")
+	}
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -113,12 +193,19 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run)
+	// Server-side dry run must not persist anything, including app record
+	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run || serverDryRun)
 
 	if err != nil {
 		return err
 	}
 
+	// Plan relies on app label to find resources when it's applied
+	if isNewApp && len(o.DeployFlags.PlanOutput) > 0 {
+		return fmt.Errorf("Expected app '%s' (namespace: %s) to exist before creating a plan for it",
+			app.Name(), app.Namespace())
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -147,11 +234,37 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	newResources, conf, nsNames, newGKs, err := o.newResources(prep, labeledResources, resourceFilter)
+	inputResources, err := o.inputResources()
+	if err != nil {
+		return err
+	}
+
+	// Config is recorded in app change (and plan) so that
+	// it could be used later, e.g. by drift and delete commands
+	var configResources []ctlres.Resource
+
+	for _, res := range inputResources {
+		if ctlconf.IsConfigResource(res) {
+			configResources = append(configResources, res.DeepCopy())
+		}
+	}
+
+	newResources, conf, nsNames, newGKs, err := o.newResources(inputResources, prep, labeledResources, resourceFilter)
 	if err != nil {
 		return err
 	}
 
+	var recordedResources []ctlres.Resource
+
+	// Resources have to be recorded to be able to roll back to them later
+	// (plan includes them since it's not known how it will be applied)
+	if o.DeployFlags.AppChangesRecordResources || o.DeployFlags.RollbackOnFailure || len(o.DeployFlags.PlanOutput) > 0 {
+		recordedResources, err = o.recordedResources(inputResources, newResources)
+		if err != nil {
+			return err
+		}
+	}
+
 	usedGKs, err := o.newAndUsedGKs(newGKs, app)
 	if err != nil {
 		return err
@@ -169,10 +282,13 @@ func (o *DeployOptions) Run() error {
 	}
 
 	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
-		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs)
+		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs, serverDryRun)
+
+	o.changeSummary = changeSummary
+
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
-			return o.presentDiffUI(clusterChangesGraph)
+			return o.presentDiffUI(clusterChangesGraph, conf)
 		}
 		return err
 	}
@@ -184,12 +300,23 @@ func (o *DeployOptions) Run() error {
 	}
 
 	if o.DiffFlags.UI {
-		return o.presentDiffUI(clusterChangesGraph)
+		return o.presentDiffUI(clusterChangesGraph, conf)
+	}
+
+	if serverDryRun {
+		return nil
 	}
 
 	if o.DiffFlags.Run || hasNoChanges {
 		o.writeAppMetadataToFile(app)
 
+		if len(o.DeployFlags.PlanOutput) > 0 {
+			err = o.writePlanToFile(app, labelSelector, changeSummary, configResources, recordedResources, clusterChangesGraph)
+			if err != nil {
+				return err
+			}
+		}
+
 		if o.DiffFlags.Run && o.DiffFlags.ExitStatus {
 			return DeployDiffExitStatus{hasNoChanges}
 		}
@@ -199,22 +326,26 @@ func (o *DeployOptions) Run() error {
 		return nil
 	}
 
-	if o.PreflightChecks != nil {
-		err = o.PreflightChecks.SetConfig(conf.PreflightRules())
-		if err != nil {
-			return fmt.Errorf("preflight configuration settings failed: %w", err)
-		}
-		err = o.PreflightChecks.Run(context.Background(), clusterChangesGraph)
+	if o.diffUIProgressEvents != nil {
+		err = o.startDiffUI(clusterChangesGraph, conf)
 		if err != nil {
-			return fmt.Errorf("preflight checks failed: %w", err)
+			return err
 		}
 	}
 
-	err = o.ui.AskForConfirmation()
+	err = o.runPreflightChecks(conf, clusterChangesGraph)
 	if err != nil {
 		return err
 	}
 
+	// Rollback was already confirmed as part of the failed deploy
+	if !o.isRollback {
+		err = o.confirmChanges(conf, clusterChangesGraph)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Track newly added GVs and GKs
 	err = app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, existingResources),
 		NewUsedGKsScope(append(newResources, existingResources...)).GKs())
@@ -228,6 +359,24 @@ func (o *DeployOptions) Run() error {
 		go o.showLogs(supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
 	}
 
+	changeDesc := "update: " + changeSummary
+	if o.isRollback {
+		changeDesc = "rollback: " + changeSummary
+	}
+
+	return o.applyChanges(app, clusterChangeSet, clusterChangesGraph, changeDesc, nsNames, configResources, recordedResources, func() error {
+		// Remove unused GVs and GKs
+		return app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, nil),
+			NewUsedGKsScope(newResources).GKs())
+	})
+}
+
+// applyChanges applies changes as part of a new app change (shared
+// by regular and plan based deploys) and rolls back on failure if requested
+func (o *DeployOptions) applyChanges(app ctlapp.App, clusterChangeSet ctlcap.ClusterChangeSet,
+	clusterChangesGraph *ctldgraph.ChangeGraph, changeDesc string, nsNames []string,
+	configResources, recordedResources []ctlres.Resource, afterApplyFunc func() error) error {
+
 	defer func() {
 		_, numDeleted, _ := app.GCChanges(o.DeployFlags.AppChangesMaxToKeep, nil)
 		if numDeleted > 0 {
@@ -235,15 +384,22 @@ func (o *DeployOptions) Run() error {
 		}
 	}()
 
+	// Only record resources when requested even if they were calculated
+	if !o.DeployFlags.AppChangesRecordResources && !o.DeployFlags.RollbackOnFailure {
+		recordedResources = nil
+	}
+
 	touch := ctlapp.Touch{
 		App:                 app,
-		Description:         "update: " + changeSummary,
+		Description:         changeDesc,
 		Namespaces:          nsNames,
 		IgnoreSuccessErr:    true,
 		AppChangesMaxToKeep: o.DeployFlags.AppChangesMaxToKeep,
+		Resources:           recordedResources,
+		Config:              configResources,
 	}
 
-	err = touch.Do(func() error {
+	err := touch.Do(func() error {
 		defer o.writeAppMetadataToFile(app)
 
 		err := clusterChangeSet.Apply(clusterChangesGraph)
@@ -251,20 +407,74 @@ func (o *DeployOptions) Run() error {
 			return err
 		}
 
-		// Remove unused GVs and GKs
-		return app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, nil),
-			NewUsedGKsScope(newResources).GKs())
+		return afterApplyFunc()
 	})
 	if err != nil {
+		if o.DeployFlags.RollbackOnFailure {
+			return o.rollback(app, err)
+		}
 		return err
 	}
 
 	if o.ApplyFlags.ExitStatus {
-		return DeployApplyExitStatus{hasNoChanges}
+		// Apply only happens when there are changes
+		return DeployApplyExitStatus{false}
 	}
 	return nil
 }
 
+// rollback deploys resources recorded in last successful app change
+// using same ordering rules as a regular deploy. Failed app change
+// stays recorded and rollback is recorded as a separate app change.
+func (o *DeployOptions) rollback(app ctlapp.App, deployErr error) error {
+	changeName, rs, err := o.lastSuccessfulChangeResources(app)
+	if err != nil {
+		return fmt.Errorf("%w (rollback was not attempted: %s)", deployErr, err)
+	}
+
+	o.ui.PrintLinef("Rolling back to resources recorded in app change '%s'", changeName)
+
+	rollbackOpts := *o
+	rollbackOpts.ResourcesFunc = func() ([]ctlres.Resource, error) { return rs, nil }
+	rollbackOpts.DeployFlags.RollbackOnFailure = false
+	rollbackOpts.DeployFlags.PlanInput = ""
+	rollbackOpts.DeployFlags.AppChangesRecordResources = true
+	rollbackOpts.DeployFlags.Logs = false // already shown by failed deploy
+	rollbackOpts.ApplyFlags.ExitStatus = false
+	// Keep report and UI of failed deploy instead of replacing them with rollback changes
+	rollbackOpts.DiffFlags.HTMLReport = ""
+	rollbackOpts.DiffFlags.UI = false
+	rollbackOpts.DiffFlags.UILive = false
+	rollbackOpts.isRollback = true
+
+	err = rollbackOpts.Run()
+	if err != nil {
+		return fmt.Errorf("%w (rollback to app change '%s' failed: %s)", deployErr, changeName, err)
+	}
+
+	return fmt.Errorf("%w (rolled back to app change '%s')", deployErr, changeName)
+}
+
+func (o *DeployOptions) lastSuccessfulChangeResources(app ctlapp.App) (string, []ctlres.Resource, error) {
+	change, err := lastSuccessfulChange(app)
+	if err != nil {
+		return "", nil, err
+	}
+	if change == nil {
+		return "", nil, fmt.Errorf("Expected to find successful app change to roll back to")
+	}
+
+	rs, err := change.Resources()
+	if err != nil {
+		return "", nil, err
+	}
+	if rs == nil {
+		return "", nil, fmt.Errorf("Expected last successful app change '%s' to have recorded resources", change.Name())
+	}
+
+	return change.Name(), rs, nil
+}
+
 func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App) ([]schema.GroupKind, error) {
 	if o.DeployFlags.DisableGKScoping {
 		return []schema.GroupKind{}, nil
@@ -310,15 +520,10 @@ func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App)
 	return uniqGKs, nil
 }
 
-func (o *DeployOptions) newResources(
+func (o *DeployOptions) newResources(newResources []ctlres.Resource,
 	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
 	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, []schema.GroupKind, error) {
 
-	newResources, err := o.newResourcesFromFiles()
-	if err != nil {
-		return nil, ctlconf.Conf{}, nil, nil, err
-	}
-
 	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
@@ -343,6 +548,33 @@ func (o *DeployOptions) newResources(
 	return resourceFilter.Apply(newResources), conf, nsNames, newGKs, nil
 }
 
+// recordedResources returns prepared resources (together with kapp config
+// used to prepare them) in a form that could be deployed again during rollback
+func (o *DeployOptions) recordedResources(inputResources, newResources []ctlres.Resource) ([]ctlres.Resource, error) {
+	recordedResources := []ctlres.Resource{}
+
+	for _, res := range inputResources {
+		// ConfigMaps labeled as kapp config are part of new resources
+		if ctlconf.IsConfigKindResource(res) {
+			recordedResources = append(recordedResources, res.DeepCopy())
+		}
+	}
+
+	preparedResources, err := ctlapp.WithoutNonces(newResources)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(recordedResources, preparedResources...), nil
+}
+
+func (o *DeployOptions) inputResources() ([]ctlres.Resource, error) {
+	if o.ResourcesFunc != nil {
+		return o.ResourcesFunc()
+	}
+	return o.newResourcesFromFiles()
+}
+
 func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	var allResources []ctlres.Resource
 
@@ -420,7 +652,7 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 }
 
 func (o *DeployOptions) calculateAndPresentChanges(existingResources,
-	newResources []ctlres.Resource, conf ctlconf.Conf, supportObjs FactorySupportObjs) (
+	newResources []ctlres.Resource, conf ctlconf.Conf, supportObjs FactorySupportObjs, serverDryRun bool) (
 	ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, bool, string, error) {
 
 	var clusterChangeSet ctlcap.ClusterChangeSet
@@ -448,19 +680,7 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 
 		changes = diffFilter.Apply(changes)
 
-		msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
-
-		convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
-			IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
-		})
-
-		clusterChangeFactory := ctlcap.NewClusterChangeFactory(
-			o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
-			changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
-
-		clusterChangeSet = ctlcap.NewClusterChangeSet(
-			changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory,
-			conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger)
+		clusterChangeSet = o.newClusterChangeSet(changes, changeFactory, changeSetFactory, conf, supportObjs)
 	}
 
 	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
@@ -469,19 +689,89 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 		return clusterChangeSet, clusterChangesGraph, false, "", err
 	}
 
-	var changesSummary string
+	if serverDryRun {
+		clusterChangeSet.DryRun(clusterChanges)
+	}
+
+	changesSummary := o.presentChanges(clusterChanges, conf)
+
+	if len(o.DiffFlags.HTMLReport) > 0 {
+		err = o.writeDiffHTMLReport(clusterChanges, clusterChangesGraph, conf)
+		if err != nil {
+			return clusterChangeSet, nil, false, "", err
+		}
+	}
 
-	{ // Present cluster changes in UI
-		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
-		changeSetView := ctlcap.NewChangeSetView(
-			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
-		changeSetView.Print(o.ui)
-		changesSummary = changeSetView.Summary()
+	if serverDryRun {
+		err = ctlcap.DryRunErr(clusterChanges)
+		if err != nil {
+			return clusterChangeSet, nil, false, "", err
+		}
 	}
 
 	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
 }
 
+func (o *DeployOptions) newClusterChangeSet(changes []ctldiff.Change, changeFactory ctldiff.ChangeFactory,
+	changeSetFactory ctldiff.ChangeSetFactory, conf ctlconf.Conf, supportObjs FactorySupportObjs) ctlcap.ClusterChangeSet {
+
+	var msgsUI ctlcap.UI = cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+
+	if o.jsonlProgressUI != nil {
+		msgsUI = o.jsonlProgressUI
+	}
+	if o.diffUIProgressEvents != nil {
+		msgsUI = ctldiffui.NewProgressUI(msgsUI, o.diffUIProgressEvents)
+	}
+
+	convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
+		IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
+		StorageClassBindingModes: ctlcap.NewStorageClassBindingModes(supportObjs.CoreClient),
+	})
+
+	clusterChangeFactory := ctlcap.NewClusterChangeFactory(
+		o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
+		changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
+
+	return ctlcap.NewClusterChangeSet(
+		changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory,
+		conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger)
+}
+
+// presentChanges prints cluster changes and returns their summary
+func (o *DeployOptions) presentChanges(clusterChanges []*ctlcap.ClusterChange, conf ctlconf.Conf) string {
+	changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
+	changeSetView := ctlcap.NewChangeSetView(
+		changeViews, conf.DiffMaskRules(), conf.DiffListMergeKeyRules(), o.DiffFlags.ChangeSetViewOpts)
+	changeSetView.Print(o.ui)
+	return changeSetView.Summary()
+}
+
+func (o *DeployOptions) confirmChanges(conf ctlconf.Conf, clusterChangesGraph *ctldgraph.ChangeGraph) error {
+	err := NewDangerousChangesConfirmation(conf.ConfirmationRules(),
+		o.ConfirmationFlags.AllowDangerousChanges, o.ui).Confirm(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	return o.ui.AskForConfirmation()
+}
+
+func (o *DeployOptions) runPreflightChecks(conf ctlconf.Conf, clusterChangesGraph *ctldgraph.ChangeGraph) error {
+	if o.PreflightChecks == nil {
+		return nil
+	}
+	err := o.PreflightChecks.SetConfig(conf.PreflightRules())
+	if err != nil {
+		return fmt.Errorf("preflight configuration settings failed: %w", err)
+	}
+	err = o.PreflightChecks.Run(context.Background(), clusterChangesGraph)
+	if err != nil {
+		return fmt.Errorf("preflight checks failed: %w", err)
+	}
+	return nil
+}
+
 func (o *DeployOptions) existingPodResources(existingResources []ctlres.Resource) []ctlres.Resource {
 	var existingPods []ctlres.Resource
 	for _, res := range existingResources {
@@ -582,9 +872,47 @@ func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
 	return names
 }
 
-func (o *DeployOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
+func (o *DeployOptions) writeDiffHTMLReport(clusterChanges []*ctlcap.ClusterChange,
+	graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+
+	report := ctldiffui.NewHTMLReport(ctlcap.ClusterChangesAsChangeViews(clusterChanges),
+		graph, conf.DiffMaskRules(), o.DiffFlags.TextDiffViewOpts)
+	return report.WriteToFile(o.DiffFlags.HTMLReport)
+}
+
+func (o *DeployOptions) presentDiffUI(graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
 	opts := ctldiffui.ServerOpts{
-		DiffDataFunc: func() *ctldgraph.ChangeGraph { return graph },
+		DiffDataFunc:     func() *ctldgraph.ChangeGraph { return graph },
+		MaskRules:        conf.DiffMaskRules(),
+		TextDiffViewOpts: o.DiffFlags.TextDiffViewOpts,
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+// notifyOutcome ends progress events with deploy outcome so that
+// failures before changes are applied (e.g. preflight checks) are reported as well
+func (o *DeployOptions) notifyOutcome(err error) {
+	var exitStatus ExitStatus
+	if errors.As(err, &exitStatus) {
+		err = nil // exit statuses are used to report number of changes
+	}
+
+	event := ctlcap.NewOutcomeProgressEvent(err)
+
+	if o.jsonlProgressUI != nil {
+		o.jsonlProgressUI.NotifyProgress(event)
+	}
+	if o.diffUIProgressEvents != nil {
+		o.diffUIProgressEvents.NotifyProgress(event)
+	}
+}
+
+func (o *DeployOptions) startDiffUI(graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	opts := ctldiffui.ServerOpts{
+		DiffDataFunc:     func() *ctldgraph.ChangeGraph { return graph },
+		MaskRules:        conf.DiffMaskRules(),
+		TextDiffViewOpts: o.DiffFlags.TextDiffViewOpts,
+		ProgressEvents:   o.diffUIProgressEvents,
+	}
+	return ctldiffui.NewServer(opts, o.ui).Start()
+}
@@ -16,6 +16,7 @@ var (
 	DiffFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Diff Flags:",
 		PrefixMatch: "diff",
+		ExactMatch:  []string{"dry-run", "plan-output"},
 	}
 	ApplyFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Apply Flags:",
@@ -23,6 +24,9 @@ var (
 		ExactMatch: []string{
 			"dangerous-allow-empty-list-of-resources",
 			"dangerous-override-ownership-of-existing-resources",
+			"rollback-on-failure",
+			"plan-input",
+			"progress-format",
 		},
 	}
 	WaitFlagGroup = cobrautil.FlagHelpSection{
@@ -30,6 +34,11 @@ var (
 		PrefixMatch: "wait",
 		ExactMatch:  []string{"wait"},
 	}
+	WatchFlagGroup = cobrautil.FlagHelpSection{
+		Title:       "Watch Flags:",
+		PrefixMatch: "watch",
+		ExactMatch:  []string{"watch"},
+	}
 	ResourceFilterFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Resource Filter Flags:",
 		PrefixMatch: "filter",
@@ -60,6 +69,7 @@ func setDeployCmdFlags(cmd *cobra.Command) {
 		DiffFlagGroup,
 		ApplyFlagGroup,
 		WaitFlagGroup,
+		WatchFlagGroup,
 		ResourceFilterFlagGroup,
 		ResourceValidationFlagGroup,
 		ResourceManglingFlagGroup,
@@ -5,6 +5,7 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
@@ -19,7 +20,10 @@ type DeployFlags struct {
 	ExistingNonLabeledResourcesCheckConcurrency int
 	OverrideOwnershipOfExistingResources        bool
 
-	AppChangesMaxToKeep int
+	AppChangesMaxToKeep       int
+	AppChangesRecordResources bool
+
+	RollbackOnFailure bool
 
 	DefaultLabelScopingRules bool
 
@@ -28,8 +32,28 @@ type DeployFlags struct {
 	AppMetadataFile string
 
 	DisableGKScoping bool
+
+	DryRun string
+
+	PlanOutput string
+	PlanInput  string
+
+	ProgressFormat string
+	ProgressFile   string
+
+	Watch         bool
+	WatchInterval time.Duration
+	WatchCoalesce time.Duration
 }
 
+const (
+	DeployDryRunNone   = "none"
+	DeployDryRunServer = "server"
+
+	DeployProgressFormatText  = "text"
+	DeployProgressFormatJSONL = "jsonl"
+)
+
 func (s *DeployFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&s.AllowCheck, "allow-check", false, "Enable client-side allowing")
 	cmd.Flags().StringSliceVar(&s.AllowedNamespaces, "allow-ns", nil, "Set allowed namespace for resources (does not apply to the app itself)")
@@ -53,6 +77,10 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 		true, "Use default label scoping rules")
 
 	cmd.Flags().IntVar(&s.AppChangesMaxToKeep, "app-changes-max-to-keep", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
+	cmd.Flags().BoolVar(&s.AppChangesRecordResources, "app-changes-record-resources", false, "Record compressed snapshot of deployed resources in app changes (used by app-change rollback)")
+
+	cmd.Flags().BoolVar(&s.RollbackOnFailure, "rollback-on-failure", false,
+		"Roll back to resources recorded in last successful app change if apply or wait fails (implies --app-changes-record-resources)")
 
 	cmd.Flags().BoolVar(&s.Logs, "logs", true, fmt.Sprintf("Show logs from Pods annotated as '%s'", deployLogsAnnKey))
 	cmd.Flags().BoolVar(&s.LogsAll, "logs-all", false, "Show logs from all Pods")
@@ -60,4 +88,55 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&s.DisableGKScoping, "dangerous-disable-gk-scoping",
 		false, "Disable scoping of resource searching to used GroupKinds")
+
+	cmd.Flags().StringVar(&s.DryRun, "dry-run", DeployDryRunNone,
+		"Send changes to the API server as server-side dry runs without persisting them (valid values: none, server)")
+
+	cmd.Flags().StringVar(&s.PlanOutput, "plan-output", "", "Set filename to write calculated changes as a plan (requires --diff-run)")
+	cmd.Flags().StringVar(&s.PlanInput, "plan-input", "", "Apply changes from a plan file instead of calculating them from --file")
+
+	cmd.Flags().StringVar(&s.ProgressFormat, "progress-format", DeployProgressFormatText,
+		"Set format of apply and wait progress output (valid values: text, jsonl)")
+	cmd.Flags().StringVar(&s.ProgressFile, "progress-file", "",
+		"Set filename to write progress events to (required by --progress-format=jsonl)")
+
+	cmd.Flags().BoolVar(&s.Watch, "watch", false, "Keep running and deploy again when local files change")
+	cmd.Flags().DurationVar(&s.WatchInterval, "watch-interval", 0,
+		"Deploy again on an interval even if files did not change, e.g. to correct drift (0 disables)")
+	cmd.Flags().DurationVar(&s.WatchCoalesce, "watch-coalesce", 1*time.Second,
+		"Wait for files to stop changing for this long before deploying again")
+}
+
+// ServerDryRun returns true if changes should only be sent as server-side dry runs
+func (s *DeployFlags) ServerDryRun() (bool, error) {
+	switch s.DryRun {
+	case "", DeployDryRunNone:
+		return false, nil
+	case DeployDryRunServer:
+		return true, nil
+	default:
+		return false, fmt.Errorf("Expected --dry-run to be one of: %s, %s (given: '%s')",
+			DeployDryRunNone, DeployDryRunServer, s.DryRun)
+	}
+}
+
+// JSONLProgress returns true if progress should be shown as line-delimited JSON events
+func (s *DeployFlags) JSONLProgress() (bool, error) {
+	switch s.ProgressFormat {
+	case "", DeployProgressFormatText:
+		if len(s.ProgressFile) > 0 {
+			return false, fmt.Errorf("Expected --progress-file to be used together with --progress-format=%s",
+				DeployProgressFormatJSONL)
+		}
+		return false, nil
+	case DeployProgressFormatJSONL:
+		if len(s.ProgressFile) == 0 {
+			return false, fmt.Errorf("Expected --progress-file to be specified when using --progress-format=%s",
+				DeployProgressFormatJSONL)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("Expected --progress-format to be one of: %s, %s (given: '%s')",
+			DeployProgressFormatText, DeployProgressFormatJSONL, s.ProgressFormat)
+	}
 }
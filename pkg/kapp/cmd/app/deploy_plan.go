@@ -0,0 +1,252 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func (o *DeployOptions) writePlanToFile(app ctlapp.App, labelSelector labels.Selector,
+	changeSummary string, configResources, recordedResources []ctlres.Resource, clusterChangesGraph *ctldgraph.ChangeGraph) error {
+
+	planApp := ctlcap.PlanApp{
+		Name:          app.Name(),
+		Namespace:     app.Namespace(),
+		LabelSelector: labelSelector.String(),
+	}
+
+	plan, err := ctlcap.NewPlan(planApp, changeSummary, configResources, recordedResources, clusterChangesGraph)
+	if err != nil {
+		return fmt.Errorf("Building plan: %w", err)
+	}
+
+	bs, err := plan.AsBytes()
+	if err != nil {
+		return fmt.Errorf("Serializing plan: %w", err)
+	}
+
+	err = os.WriteFile(o.DeployFlags.PlanOutput, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing plan: %w", err)
+	}
+
+	o.ui.PrintLinef("Wrote plan with %d changes to '%s'", len(plan.Changes), o.DeployFlags.PlanOutput)
+
+	return nil
+}
+
+// runPlan applies changes exactly as they were recorded in the plan
+// (including ordering) instead of calculating them from input files
+func (o *DeployOptions) runPlan(serverDryRun bool) error {
+	if len(o.FileFlags.Files) > 0 || o.ResourcesFunc != nil {
+		return fmt.Errorf("Expected --file (-f) to not be specified together with --plan-input")
+	}
+	if len(o.DeployFlags.PlanOutput) > 0 {
+		return fmt.Errorf("Expected --plan-output to not be specified together with --plan-input")
+	}
+
+	planBs, err := os.ReadFile(o.DeployFlags.PlanInput)
+	if err != nil {
+		return fmt.Errorf("Reading plan: %w", err)
+	}
+
+	plan, err := ctlcap.NewPlanFromBytes(planBs)
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	if app.Name() != plan.App.Name || app.Namespace() != plan.App.Namespace {
+		return fmt.Errorf("Expected plan to be for app '%s' (namespace: %s), but was for app '%s' (namespace: %s)",
+			app.Name(), app.Namespace(), plan.App.Name, plan.App.Namespace)
+	}
+
+	appLabels, err := o.LabelFlags.AsMap()
+	if err != nil {
+		return err
+	}
+
+	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run || serverDryRun)
+	if err != nil {
+		return err
+	}
+
+	if isNewApp {
+		return fmt.Errorf("Expected app '%s' (namespace: %s) to exist since plan was created for an existing app",
+			app.Name(), app.Namespace())
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	if labelSelector.String() != plan.App.LabelSelector {
+		return fmt.Errorf("Expected app label selector '%s' to match plan label selector '%s'",
+			labelSelector.String(), plan.App.LabelSelector)
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	configResources, err := plan.ConfigResources()
+	if err != nil {
+		return err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(configResources)
+	if err != nil {
+		return err
+	}
+
+	changeOpts := ctldiff.ChangeOpts{AllowAnchoredDiff: o.DiffFlags.AnchoredDiff}
+
+	changes, err := plan.DiffChanges(supportObjs.IdentifiedResources, changeOpts)
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(), conf.DiffAgainstExistingFieldExclusionMods(), changeOpts)
+	changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
+
+	clusterChangeSet := o.newClusterChangeSet(changes, changeFactory, changeSetFactory, conf, supportObjs)
+
+	clusterChanges, clusterChangesGraph, err := clusterChangeSet.CalculateFromPlan(plan)
+	if err != nil {
+		return err
+	}
+
+	if serverDryRun {
+		clusterChangeSet.DryRun(clusterChanges)
+	}
+
+	changeSummary := o.presentChanges(clusterChanges, conf)
+
+	if serverDryRun {
+		return ctlcap.DryRunErr(clusterChanges)
+	}
+
+	hasNoChanges := len(clusterChanges) == 0
+
+	if o.DiffFlags.Run || hasNoChanges {
+		if o.DiffFlags.Run && o.DiffFlags.ExitStatus {
+			return DeployDiffExitStatus{hasNoChanges}
+		}
+		if o.ApplyFlags.ExitStatus {
+			return DeployApplyExitStatus{hasNoChanges}
+		}
+		return nil
+	}
+
+	err = o.runPreflightChecks(conf, clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	err = o.confirmChanges(conf, clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	var newResources, existingResources []ctlres.Resource
+
+	for _, change := range changes {
+		if res := change.NewResource(); res != nil {
+			newResources = append(newResources, res)
+		}
+		if res := change.ExistingResource(); res != nil {
+			existingResources = append(existingResources, res)
+		}
+	}
+
+	// Plan only includes changed resources, hence previously
+	// used GVs and GKs are kept instead of being recalculated
+	usedGKs, err := o.planUsedGKs(app, append(newResources, existingResources...))
+	if err != nil {
+		return err
+	}
+
+	// Track newly added GVs and GKs
+	err = app.UpdateUsedGVsAndGKs(append(usedGVs, failingAPIServicesPolicy.GVs(newResources, existingResources)...), usedGKs)
+	if err != nil {
+		return err
+	}
+
+	recordedResources, err := plan.RecordedResources()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	nsNames := o.planNsNames(meta.LastChange.Namespaces, newResources)
+
+	return o.applyChanges(app, clusterChangeSet, clusterChangesGraph, "update: "+changeSummary,
+		nsNames, configResources, recordedResources, func() error { return nil })
+}
+
+// planUsedGKs returns previously used GKs together
+// with GKs of given resources without duplicates
+func (o *DeployOptions) planUsedGKs(app ctlapp.App, resources []ctlres.Resource) ([]schema.GroupKind, error) {
+	usedGKs, err := app.UsedGKs()
+	if err != nil {
+		return nil, err
+	}
+
+	var gks []schema.GroupKind
+	if usedGKs != nil {
+		gks = append(gks, *usedGKs...)
+	}
+	gks = append(gks, NewUsedGKsScope(resources).GKs()...)
+
+	gksByGK := map[schema.GroupKind]struct{}{}
+	var uniqGKs []schema.GroupKind
+
+	for _, gk := range gks {
+		if _, found := gksByGK[gk]; !found {
+			gksByGK[gk] = struct{}{}
+			uniqGKs = append(uniqGKs, gk)
+		}
+	}
+
+	return uniqGKs, nil
+}
+
+func (o *DeployOptions) planNsNames(prevNsNames []string, newResources []ctlres.Resource) []string {
+	uniqNames := map[string]struct{}{}
+	var names []string
+	for _, name := range append(prevNsNames, o.nsNames(newResources)...) {
+		if _, found := uniqNames[name]; !found {
+			names = append(names, name)
+			uniqNames[name] = struct{}{}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
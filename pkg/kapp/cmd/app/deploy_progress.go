@@ -0,0 +1,72 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// JSONLProgressUI writes each progress event (and each plain message)
+// as a single line JSON object so that it could be consumed by other tools.
+// Events are written to a dedicated writer (e.g. file) so that
+// they are not interleaved with other output.
+type JSONLProgressUI struct {
+	writer     io.Writer
+	writerLock sync.Mutex
+}
+
+var _ ctlcap.UI = &JSONLProgressUI{}
+var _ ctlcap.ProgressUI = &JSONLProgressUI{}
+
+func NewJSONLProgressUI(writer io.Writer) *JSONLProgressUI {
+	return &JSONLProgressUI{writer: writer}
+}
+
+func (u *JSONLProgressUI) NotifySection(msg string, args ...interface{}) {
+	u.NotifyProgress(ctlcap.ProgressEvent{
+		Type:    ctlcap.ProgressEventTypeSection,
+		Message: fmt.Sprintf(msg, args...),
+	})
+}
+
+func (u *JSONLProgressUI) Notify(msgs []string) {
+	for _, msg := range msgs {
+		u.NotifyProgress(ctlcap.ProgressEvent{
+			Type:    ctlcap.ProgressEventTypeMessage,
+			Message: msg,
+		})
+	}
+}
+
+func (u *JSONLProgressUI) NotifyProgress(event ctlcap.ProgressEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		// Report failure as an error event so that stream stays valid JSONL
+		bs, err = json.Marshal(ctlcap.ProgressEvent{
+			Type:    ctlcap.ProgressEventTypeMessage,
+			Time:    event.Time,
+			State:   "error",
+			Message: fmt.Sprintf("Marshaling progress event '%s': %s", event.Type, err),
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	u.writerLock.Lock()
+	defer u.writerLock.Unlock()
+
+	// Nothing else could be reported if writing fails
+	_, _ = u.writer.Write(append(bs, '\n'))
+}
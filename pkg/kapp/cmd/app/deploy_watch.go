@@ -0,0 +1,251 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	deployWatchPollInterval = 500 * time.Millisecond
+)
+
+// runWatch repeatedly deploys app whenever watched files change
+// (or on an interval) until interrupted. Failed deploys do not stop watching.
+func (o *DeployOptions) runWatch() error {
+	paths, err := o.watchedPaths()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Restore default signal handling after first interrupt
+	// so that second interrupt stops an in-progress deploy
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	watcher := deployFileWatcher{fsys: o.FileSystem, paths: paths}
+
+	snapshot, err := watcher.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	reason := "initial"
+
+	for cycle := 1; ; cycle++ {
+		o.runWatchCycle(cycle, reason)
+
+		if len(paths) > 0 {
+			o.ui.PrintLinef("Watching %d files for changes (interrupt to stop)", len(snapshot))
+		} else {
+			o.ui.PrintLinef("Waiting %s before deploying again (interrupt to stop)", o.DeployFlags.WatchInterval)
+		}
+
+		reason, snapshot, err = o.waitForWatchTrigger(ctx, watcher, snapshot)
+		if err != nil {
+			return err
+		}
+		if len(reason) == 0 {
+			return nil
+		}
+	}
+}
+
+func (o *DeployOptions) runWatchCycle(cycle int, reason string) {
+	cycleOpts := *o
+	cycleOpts.DeployFlags.Watch = false
+	cycleOpts.DiffFlags.ExitStatus = false
+	cycleOpts.ApplyFlags.ExitStatus = false
+
+	startTime := time.Now()
+
+	err := cycleOpts.Run()
+
+	summary := cycleOpts.changeSummary
+	if len(summary) == 0 {
+		summary = "changes not calculated"
+	}
+
+	duration := time.Since(startTime).Round(time.Millisecond)
+
+	if err != nil {
+		o.ui.ErrorLinef("Watch cycle %d (%s): failed in %s (%s): %s", cycle, reason, duration, summary, err)
+	} else {
+		o.ui.PrintLinef("Watch cycle %d (%s): succeeded in %s (%s)", cycle, reason, duration, summary)
+	}
+}
+
+// waitForWatchTrigger blocks until next deploy should happen and returns its reason.
+// Empty reason is returned when watching was stopped.
+func (o *DeployOptions) waitForWatchTrigger(ctx context.Context,
+	watcher deployFileWatcher, snapshot deployFileSnapshot) (string, deployFileSnapshot, error) {
+
+	var intervalCh <-chan time.Time
+
+	if o.DeployFlags.WatchInterval > 0 {
+		intervalTimer := time.NewTimer(o.DeployFlags.WatchInterval)
+		defer intervalTimer.Stop()
+		intervalCh = intervalTimer.C
+	}
+
+	pollTicker := time.NewTicker(deployWatchPollInterval)
+	defer pollTicker.Stop()
+
+	// Coalesce bursts of changes (e.g. editors writing several files)
+	// by waiting until files stop changing for a period of time
+	var lastChangedAt time.Time
+	var changedPaths []string
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", snapshot, nil
+
+		case <-intervalCh:
+			if len(changedPaths) > 0 {
+				return o.filesChangedReason(changedPaths), snapshot, nil
+			}
+			return "interval", snapshot, nil
+
+		case <-pollTicker.C:
+			newSnapshot, err := watcher.Snapshot()
+			if err != nil {
+				// Files may be temporarily missing while being saved
+				o.logger.Debug("watch: snapshotting files: %s", err)
+				continue
+			}
+
+			if paths := snapshot.ChangedPaths(newSnapshot); len(paths) > 0 {
+				changedPaths = append(changedPaths, paths...)
+				lastChangedAt = time.Now()
+				snapshot = newSnapshot
+				continue
+			}
+
+			if len(changedPaths) > 0 && time.Since(lastChangedAt) >= o.DeployFlags.WatchCoalesce {
+				return o.filesChangedReason(changedPaths), snapshot, nil
+			}
+		}
+	}
+}
+
+func (o *DeployOptions) filesChangedReason(paths []string) string {
+	uniqPaths := map[string]struct{}{}
+	for _, path := range paths {
+		uniqPaths[path] = struct{}{}
+	}
+	if len(uniqPaths) == 1 {
+		return fmt.Sprintf("changed %s", paths[0])
+	}
+	return fmt.Sprintf("changed %d files", len(uniqPaths))
+}
+
+func (o *DeployOptions) watchedPaths() ([]string, error) {
+	if len(o.DeployFlags.PlanInput) > 0 || len(o.DeployFlags.PlanOutput) > 0 {
+		return nil, fmt.Errorf("Expected --watch to not be used together with --plan-input or --plan-output")
+	}
+	if o.DiffFlags.UI {
+		return nil, fmt.Errorf("Expected --watch to not be used together with --diff-ui")
+	}
+
+	var paths []string
+
+	for _, file := range o.FileFlags.Files {
+		switch {
+		case file == "-":
+			return nil, fmt.Errorf("Expected --watch to not be used with stdin (--file -) since it cannot be read again")
+		case strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://"):
+			// Remote files are fetched again on each deploy, but not watched
+		default:
+			paths = append(paths, file)
+		}
+	}
+
+	if len(paths) == 0 && o.DeployFlags.WatchInterval <= 0 {
+		return nil, fmt.Errorf("Expected --watch to be used with at least one local --file or --watch-interval")
+	}
+
+	return paths, nil
+}
+
+type deployFileWatcher struct {
+	fsys  fs.FS
+	paths []string
+}
+
+type deployFileStat struct {
+	ModTime time.Time
+	Size    int64
+}
+
+type deployFileSnapshot map[string]deployFileStat
+
+// Snapshot records modification times of watched files. Polling is used
+// (instead of OS specific notifications) since number of files is typically small.
+func (w deployFileWatcher) Snapshot() (deployFileSnapshot, error) {
+	snapshot := deployFileSnapshot{}
+
+	for _, path := range w.paths {
+		err := w.walkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			snapshot[path] = deployFileStat{ModTime: info.ModTime(), Size: info.Size()}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Watching file '%s': %w", path, err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (w deployFileWatcher) walkDir(path string, fn fs.WalkDirFunc) error {
+	// Same as file resources, command line invocation does not set file system
+	if w.fsys == nil {
+		return filepath.WalkDir(path, fn)
+	}
+	return fs.WalkDir(w.fsys, path, fn)
+}
+
+// ChangedPaths returns paths that were added, removed or modified
+func (s deployFileSnapshot) ChangedPaths(other deployFileSnapshot) []string {
+	var paths []string
+
+	for path, stat := range other {
+		prevStat, found := s[path]
+		if !found || !prevStat.ModTime.Equal(stat.ModTime) || prevStat.Size != stat.Size {
+			paths = append(paths, path)
+		}
+	}
+
+	for path := range s {
+		if _, found := other[path]; !found {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
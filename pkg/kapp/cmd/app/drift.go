@@ -0,0 +1,191 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type DriftOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+
+	Changes bool
+	ctldiff.TextDiffViewOpts
+
+	ExitStatus bool
+
+	// ConfigFiles provide kapp config (e.g. rebase rules)
+	// instead of config recorded in last successful app change
+	ConfigFiles []string
+}
+
+func NewDriftOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DriftOptions {
+	return &DriftOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDriftCmd(o *DriftOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Show resources that changed on the cluster since they were last deployed",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Show drifted resources of app 'app1'
+  kapp drift -a app1
+
+  # Show drifted fields with their values
+  kapp drift -a app1 -c
+
+  # Exit with status 3 if any resource drifted (or 2 if nothing drifted)
+  kapp drift -a app1 --exit-status
+
+  # Show drifted resources of app 'app1' using rebase rules from config/kapp.yml
+  kapp drift -a app1 -f config/kapp.yml`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().BoolVarP(&o.Changes, "diff-changes", "c", false, "Show drifted changes")
+	cmd.Flags().IntVar(&o.Context, "diff-context", 2, "Show number of lines around changed lines")
+	cmd.Flags().BoolVar(&o.LineNumbers, "diff-line-numbers", true, "Show line numbers")
+	cmd.Flags().BoolVar(&o.Mask, "diff-mask", true, "Apply masking rules")
+	cmd.Flags().BoolVar(&o.ExitStatus, "exit-status", false, "Return specific exit status based on whether any resources drifted")
+	cmd.Flags().StringSliceVarP(&o.ConfigFiles, "file", "f", nil,
+		"Set file with kapp config, e.g. rebase rules, instead of config recorded in last successful app change; "+
+			"other resources are ignored (format: /tmp/foo, https://..., -) (can repeat)")
+	return cmd
+}
+
+func (o *DriftOptions) Run() error {
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	resources = resourceFilter.Apply(resources)
+
+	configRs, err := o.configResources(app)
+	if err != nil {
+		return err
+	}
+
+	// Kapp config deployed as part of the app (via labeled ConfigMaps) is used as well
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(append(configRs, resources...))
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(),
+		conf.DiffAgainstLastAppliedFieldExclusionMods(), conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	var items []DriftItem
+
+	for _, res := range resources {
+		// Resources owned by other resources (e.g. Pods) are not deployed by kapp
+		if res.Transient() {
+			continue
+		}
+
+		item, err := o.driftItem(res, changeFactory)
+		if err != nil {
+			return fmt.Errorf("Calculating drift for resource '%s': %w", res.Description(), err)
+		}
+
+		items = append(items, item)
+	}
+
+	view := DriftView{Source: fmt.Sprintf("app '%s'", app.Name()), Items: items}
+
+	if o.Changes {
+		view.PrintChanges(o.ui, conf.DiffMaskRules(), o.TextDiffViewOpts)
+	}
+
+	err = view.Print(o.ui)
+	if err != nil {
+		return err
+	}
+
+	if o.ExitStatus {
+		return DriftExitStatus{HasDrift: view.NumDrifted() > 0}
+	}
+	return nil
+}
+
+// configResources returns config provided via files or otherwise
+// config recorded in last successful app change (same config is
+// used for rebase rules and diff mask rules as during deploy)
+func (o *DriftOptions) configResources(app ctlapp.App) ([]ctlres.Resource, error) {
+	if len(o.ConfigFiles) > 0 {
+		return configResourcesFromFiles(o.ConfigFiles)
+	}
+	return lastSuccessfulChangeConfig(app)
+}
+
+func (o *DriftOptions) driftItem(res ctlres.Resource, changeFactory ctldiff.ChangeFactory) (DriftItem, error) {
+	lastAppliedRes, err := changeFactory.NewResourceWithHistory(res).RecordedLastAppliedResource()
+	if err != nil {
+		return DriftItem{}, err
+	}
+
+	if lastAppliedRes == nil {
+		return DriftItem{Resource: res}, nil
+	}
+
+	// Calculate change as if last applied resource was deployed again
+	// so that same rebase rules and field exclusions are taken into account
+	change, err := changeFactory.NewChangeAgainstLastApplied(res, lastAppliedRes)
+	if err != nil {
+		return DriftItem{}, err
+	}
+
+	return DriftItem{Resource: res, Change: change}, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+)
+
+type DriftExitStatus struct {
+	HasDrift bool
+}
+
+var _ ExitStatus = DriftExitStatus{}
+
+func (d DriftExitStatus) Error() string {
+	driftStr := "no drift"
+	if d.HasDrift {
+		driftStr = "drift"
+	}
+	return fmt.Sprintf("Exiting after detecting %s (exit status %d)", driftStr, d.ExitStatus())
+}
+
+func (d DriftExitStatus) ExitStatus() int {
+	if d.HasDrift {
+		return 3
+	}
+	return 2
+}
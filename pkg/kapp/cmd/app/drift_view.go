@@ -0,0 +1,123 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type DriftItem struct {
+	Resource ctlres.Resource
+	// Change is nil if resource does not have recorded last applied copy
+	Change ctldiff.Change
+}
+
+func (i DriftItem) HasHistory() bool { return i.Change != nil }
+
+func (i DriftItem) Drifted() bool {
+	return i.Change != nil && i.Change.Op() == ctldiff.ChangeOpUpdate
+}
+
+type DriftView struct {
+	Source string
+	Items  []DriftItem
+}
+
+func (v DriftView) Print(ui ui.UI) error {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Drift in %s", v.Source),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Drift"),
+			uitable.NewHeader("Fields"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+			{Column: 2, Asc: true},
+		},
+
+		Notes: []string{v.summary()},
+	}
+
+	for _, item := range v.Items {
+		var driftVal uitable.Value
+		var fields []string
+
+		switch {
+		case !item.HasHistory():
+			driftVal = uitable.ValueFmt{V: uitable.NewValueString("unknown"), Error: false}
+		case item.Drifted():
+			driftVal = uitable.ValueFmt{V: uitable.NewValueString("drifted"), Error: true}
+
+			var err error
+			fields, err = item.Change.OpsDiff().Paths()
+			if err != nil {
+				return fmt.Errorf("Determining drifted fields of resource '%s': %w", item.Resource.Description(), err)
+			}
+		default:
+			driftVal = uitable.NewValueString("none")
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(item.Resource.Namespace()),
+			uitable.NewValueString(item.Resource.Name()),
+			uitable.NewValueString(item.Resource.Kind()),
+			driftVal,
+			uitable.NewValueStrings(fields),
+		})
+	}
+
+	ui.PrintTable(table)
+
+	return nil
+}
+
+func (v DriftView) PrintChanges(ui ui.UI, maskRules []ctlconf.DiffMaskRule, opts ctldiff.TextDiffViewOpts) {
+	for _, item := range v.Items {
+		if item.Drifted() {
+			textDiffView := ctldiff.NewTextDiffView(item.Change.ConfigurableTextDiff(), maskRules, opts)
+			ui.BeginLinef("@@ drifted %s @@\n", item.Resource.Description())
+			ui.PrintBlock([]byte(textDiffView.String()))
+		}
+	}
+}
+
+func (v DriftView) NumDrifted() int {
+	var num int
+	for _, item := range v.Items {
+		if item.Drifted() {
+			num++
+		}
+	}
+	return num
+}
+
+func (v DriftView) summary() string {
+	var numUnknown int
+	for _, item := range v.Items {
+		if !item.HasHistory() {
+			numUnknown++
+		}
+	}
+
+	parts := []string{fmt.Sprintf("%d drifted", v.NumDrifted())}
+	if numUnknown > 0 {
+		parts = append(parts, fmt.Sprintf("%d unknown (no last applied copy)", numUnknown))
+	}
+	return "Summary: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/permissions"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// Verbs used to apply, wait on and delete app resources
+	rbacForAppResourceVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// Verbs used to determine state of associated resources (e.g. Pods of a Deployment)
+	rbacForAppAssociatedResourceVerbs = []string{"get", "list", "watch"}
+	// Verbs used to manage app record and app changes
+	rbacForAppRecordVerbs = []string{"get", "list", "create", "update", "delete"}
+)
+
+type RBACForAppOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags           Flags
+	FileFlags          cmdtools.FileFlags
+	ResourceTypesFlags ResourceTypesFlags
+
+	RBACName       string
+	ServiceAccount string
+
+	FileSystem fs.FS
+}
+
+func NewRBACForAppOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *RBACForAppOptions {
+	return &RBACForAppOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewRBACForAppCmd(o *RBACForAppOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rbac-for-app",
+		Short: "Generate minimal RBAC rules required to deploy app",
+		Long: `Generate minimal RBAC rules required to deploy app.
+
+Rules are calculated for resources provided via --file or, if no files
+are provided, for resources of an existing app. Namespaced resources are
+covered by Roles (one per namespace), cluster scoped resources by a ClusterRole.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Generate RBAC for resources in config/
+  kapp tools rbac-for-app -f config/
+
+  # Generate RBAC bound to a service account for an existing app
+  kapp tools rbac-for-app -a app1 --service-account ci/deployer`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.FileFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().StringVar(&o.RBACName, "rbac-name", "kapp-deployer", "Set name of generated (Cluster)Roles and bindings")
+	cmd.Flags().StringVar(&o.ServiceAccount, "service-account", "", "Bind generated rules to service account (format: ns/name)")
+	return cmd
+}
+
+func (o *RBACForAppOptions) Run() error {
+	supportObjs, err := FactoryClients(o.depsFactory, o.AppFlags.NamespaceFlags, o.AppFlags.AppNamespace, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	mapper, err := o.depsFactory.RESTMapper()
+	if err != nil {
+		return err
+	}
+
+	var resources []ctlres.Resource
+	var conf ctlconf.Conf
+	var appNamespace string
+
+	if len(o.FileFlags.Files) > 0 {
+		resources, conf, err = o.fileResources(supportObjs)
+		appNamespace = o.AppFlags.AppNamespace
+		if len(appNamespace) == 0 {
+			appNamespace = o.AppFlags.NamespaceFlags.Name
+		}
+	} else {
+		var app ctlapp.App
+		app, err = supportObjs.Apps.Find(o.AppFlags.Name)
+		if err == nil {
+			resources, err = o.appResources(app, supportObjs)
+			appNamespace = app.Namespace()
+		}
+		if err == nil {
+			_, conf, err = ctlconf.NewConfFromResourcesWithDefaults(nil)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{})
+
+	rules := permissions.NewRequiredRules()
+	rules.Add(appNamespace, schema.GroupResource{Resource: "configmaps"}, rbacForAppRecordVerbs...)
+
+	for _, res := range resources {
+		gr := o.groupResource(mapper, res)
+		rules.Add(res.Namespace(), gr, rbacForAppResourceVerbs...)
+
+		// Allow managing (Cluster)Roles and (Cluster)RoleBindings
+		// without holding all permissions they grant
+		if gr.Group == rbacv1.GroupName {
+			switch res.Kind() {
+			case "Role", "ClusterRole":
+				rules.Add(res.Namespace(), gr, "escalate")
+			case "RoleBinding", "ClusterRoleBinding":
+				rules.Add(res.Namespace(), gr, "bind")
+			}
+		}
+
+		convergedRes := convergedResFactory.New(res, nil, nil)
+
+		// Associated resources declared by wait rules (e.g. created by operators)
+		for _, gk := range convergedRes.AssociatedGroupKinds() {
+			mapping, err := mapper.RESTMapping(gk)
+			if err != nil {
+				return fmt.Errorf("Finding associated resource type for '%s': %w", gk, err)
+			}
+			rules.Add(res.Namespace(), mapping.Resource.GroupResource(), rbacForAppAssociatedResourceVerbs...)
+		}
+
+		for _, ref := range convergedRes.AssociatedResourceRefs() {
+			rules.Add(res.Namespace(), ref.GroupResource(), rbacForAppAssociatedResourceVerbs...)
+
+			// Logs are streamed from Pods during deploy
+			if ref.GroupResource() == (schema.GroupResource{Resource: "pods"}) {
+				rules.Add(res.Namespace(), schema.GroupResource{Resource: "pods/log"}, "get")
+			}
+		}
+	}
+
+	rbacResources, err := o.rbacResources(rules)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range rbacResources {
+		resBs, err := res.AsYAMLBytes()
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintBlock(append([]byte("---\n"), resBs...))
+	}
+
+	return nil
+}
+
+func (o *RBACForAppOptions) fileResources(supportObjs FactorySupportObjs) ([]ctlres.Resource, ctlconf.Conf, error) {
+	var resources []ctlres.Resource
+
+	for _, file := range o.FileFlags.Files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		for _, fileRes := range fileRs {
+			rs, err := fileRes.Resources()
+			if err != nil {
+				return nil, ctlconf.Conf{}, err
+			}
+
+			resources = append(resources, rs...)
+		}
+	}
+
+	// Config resources are not applied, but may contain wait rules
+	resources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(resources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, ctlapp.PrepareResourcesOpts{
+		BeforeModificationFunc: func(rs []ctlres.Resource) []ctlres.Resource { return rs },
+		DefaultNamespace:       o.AppFlags.NamespaceFlags.Name,
+	})
+
+	resources, err = prep.PrepareResources(resources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	return resources, conf, nil
+}
+
+func (o *RBACForAppOptions) appResources(app ctlapp.App, supportObjs FactorySupportObjs) ([]ctlres.Resource, error) {
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	appMeta, err := app.Meta()
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: appMeta.LastChange.Namespaces})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range resources {
+		// Resources created by controllers (e.g. ReplicaSets) are not
+		// applied by kapp, they are covered by associated resources rules
+		if len(res.OwnerRefs()) == 0 {
+			result = append(result, res)
+		}
+	}
+
+	return result, nil
+}
+
+func (o *RBACForAppOptions) groupResource(mapper meta.RESTMapper, res ctlres.Resource) schema.GroupResource {
+	mapping, err := mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
+	if err == nil {
+		return mapping.Resource.GroupResource()
+	}
+
+	// Resource type may not be known yet (e.g. CRD is part of the same app)
+	gvr, _ := meta.UnsafeGuessKindToResource(res.GroupVersion().WithKind(res.Kind()))
+	return gvr.GroupResource()
+}
+
+func (o *RBACForAppOptions) rbacResources(rules *permissions.RequiredRules) ([]ctlres.Resource, error) {
+	var subjects []rbacv1.Subject
+
+	if len(o.ServiceAccount) > 0 {
+		pieces := strings.Split(o.ServiceAccount, "/")
+		if len(pieces) != 2 || len(pieces[0]) == 0 || len(pieces[1]) == 0 {
+			return nil, fmt.Errorf("Expected service account '%s' to be in format 'ns/name'", o.ServiceAccount)
+		}
+		subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: pieces[0], Name: pieces[1]}}
+	}
+
+	var objs []runtime.Object
+
+	if rules.HasClusterRules() {
+		objs = append(objs, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: o.RBACName},
+			Rules:      rules.PolicyRules(""),
+		})
+
+		if len(subjects) > 0 {
+			objs = append(objs, &rbacv1.ClusterRoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: o.RBACName},
+				Subjects:   subjects,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: o.RBACName},
+			})
+		}
+	}
+
+	for _, ns := range rules.Namespaces() {
+		objs = append(objs, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: o.RBACName, Namespace: ns},
+			Rules:      rules.PolicyRules(ns),
+		})
+
+		if len(subjects) > 0 {
+			objs = append(objs, &rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: o.RBACName, Namespace: ns},
+				Subjects:   subjects,
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: o.RBACName},
+			})
+		}
+	}
+
+	var result []ctlres.Resource
+
+	for _, obj := range objs {
+		unObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		un := unstructured.Unstructured{Object: unObj}
+		unstructured.RemoveNestedField(un.Object, "metadata", "creationTimestamp")
+
+		result = append(result, ctlres.NewResourceUnstructured(un, ctlres.ResourceType{}))
+	}
+
+	return result, nil
+}
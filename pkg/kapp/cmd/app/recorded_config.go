@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// lastSuccessfulChange returns most recent successful app change
+// (nil is returned if app does not have one)
+func lastSuccessfulChange(app ctlapp.App) (ctlapp.Change, error) {
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Changes are sorted oldest first
+	for i := len(changes) - 1; i >= 0; i-- {
+		successful := changes[i].Meta().Successful
+		if successful != nil && *successful {
+			return changes[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lastSuccessfulChangeConfig returns kapp config resources recorded
+// in most recent successful app change (nil if none were recorded)
+func lastSuccessfulChangeConfig(app ctlapp.App) ([]ctlres.Resource, error) {
+	change, err := lastSuccessfulChange(app)
+	if err != nil || change == nil {
+		return nil, err
+	}
+	return change.Config()
+}
+
+// configResourcesFromFiles returns resources from given files
+// (callers are expected to only use kapp config out of them)
+func configResourcesFromFiles(files []string) ([]ctlres.Resource, error) {
+	var configRs []ctlres.Resource
+
+	for _, file := range files {
+		fileRs, err := ctlres.NewFileResources(nil, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return nil, err
+			}
+			configRs = append(configRs, resources...)
+		}
+	}
+
+	return configRs, nil
+}
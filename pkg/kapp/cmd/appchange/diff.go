@@ -0,0 +1,165 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type DiffOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags          cmdapp.Flags
+	ChangeSetViewOpts ctlcap.ChangeSetViewOpts
+	AnchoredDiff      bool
+}
+
+func NewDiffOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DiffOptions {
+	return &DiffOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDiffCmd(o *DiffOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <change-a> <change-b>",
+		Short: "Diff resources recorded in two app changes",
+		Args:  cobra.ExactArgs(2),
+		RunE:  func(_ *cobra.Command, args []string) error { return o.Run(args[0], args[1]) },
+		Annotations: map[string]string{
+			cmdcore.ExtraArgsKey: "",
+		},
+		Example: `
+  # Show diff between resources recorded in two app changes of app 'app1'
+  kapp app-change diff -a app1 app1-change-abcde app1-change-fghij --changes`,
+	}
+
+	o.AppFlags.Set(cmd, flagsFactory)
+
+	cmd.Flags().BoolVar(&o.ChangeSetViewOpts.Summary, "summary", true, "Show diff summary")
+	cmd.Flags().BoolVarP(&o.ChangeSetViewOpts.Changes, "changes", "c", false, "Show changes")
+	cmd.Flags().StringVar(&o.ChangeSetViewOpts.Format, "format", ctlcap.ChangeSetViewFormatText,
+		"Set format of shown changes (text, json, unified, json-patch, semantic)")
+	cmd.Flags().IntVar(&o.ChangeSetViewOpts.Context, "context", 2, "Show number of lines around changed lines")
+	cmd.Flags().BoolVar(&o.ChangeSetViewOpts.LineNumbers, "line-numbers", true, "Show line numbers")
+	cmd.Flags().BoolVar(&o.ChangeSetViewOpts.Mask, "mask", true, "Apply masking rules")
+	cmd.Flags().BoolVar(&o.ChangeSetViewOpts.ChangesYAML, "changes-yaml", false, "Print YAML of resources recorded in second app change")
+	cmd.Flags().BoolVar(&o.AnchoredDiff, "anchored", false, "Allow using anchored diff for large resources")
+
+	return cmd
+}
+
+func (o *DiffOptions) Run(changeNameA, changeNameB string) error {
+	err := o.ChangeSetViewOpts.Validate()
+	if err != nil {
+		return err
+	}
+
+	app, supportObjs, err := cmdapp.Factory(o.depsFactory, o.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("%s", notExistsMsg)
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, ctlapp.PrepareResourcesOpts{
+		BeforeModificationFunc: func(rs []ctlres.Resource) []ctlres.Resource { return rs },
+		DefaultNamespace:       o.AppFlags.NamespaceFlags.Name,
+	})
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	existingResources, _, err := o.changeResources(changes, changeNameA, prep, labeledResources)
+	if err != nil {
+		return err
+	}
+
+	newResources, conf, err := o.changeResources(changes, changeNameB, prep, labeledResources)
+	if err != nil {
+		return err
+	}
+
+	// Use rules of the second app change, same as deploy uses rules of new resources
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{AllowAnchoredDiff: o.AnchoredDiff})
+
+	// Recorded resources do not carry last applied copies, hence diff exactly
+	diffChanges, err := ctldiff.NewChangeSet(existingResources, newResources, ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	if err != nil {
+		return err
+	}
+
+	var changeViews []ctlcap.ChangeView
+
+	for _, change := range diffChanges {
+		changeViews = append(changeViews, cmdtools.NewDiffChangeView(change))
+	}
+
+	ctlcap.NewChangeSetView(changeViews, conf.DiffMaskRules(), conf.DiffListMergeKeyRules(), o.ChangeSetViewOpts).Print(o.ui)
+
+	return nil
+}
+
+func (o *DiffOptions) changeResources(changes []ctlapp.Change, name string, prep ctlapp.Preparation,
+	labeledResources *ctlres.LabeledResources) ([]ctlres.Resource, ctlconf.Conf, error) {
+
+	rs, err := recordedChangeResources(changes, name)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	// Separate kapp configuration from recorded resources
+	rs, conf, err := ctlconf.NewConfFromResourcesWithDefaults(rs)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	// Prepare resources same way as deploy does (e.g. default namespace, app labels)
+	rs, err = prep.PrepareResources(rs)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	err = labeledResources.Prepare(rs, conf.OwnershipLabelMods(), conf.LabelScopingMods(true), conf.AdditionalLabels())
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	// Nonces are regenerated every time resources are prepared
+	rs, err = ctlapp.WithoutNonces(rs)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	return rs, conf, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"fmt"
+
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func recordedChangeResources(changes []ctlapp.Change, name string) ([]ctlres.Resource, error) {
+	for _, change := range changes {
+		if change.Name() != name {
+			continue
+		}
+
+		rs, err := change.Resources()
+		if err != nil {
+			return nil, err
+		}
+
+		if rs == nil {
+			return nil, fmt.Errorf("Expected app change '%s' to have recorded resources "+
+				"(resources are recorded when deploying with --app-changes-record-resources)", name)
+		}
+
+		return rs, nil
+	}
+
+	return nil, fmt.Errorf("Expected to find app change '%s'", name)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/cobrautil"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/preflight"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type RollbackOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	DeployOptions *cmdapp.DeployOptions
+	ToChangeName  string
+}
+
+func NewRollbackOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *RollbackOptions {
+	return &RollbackOptions{
+		ui:            ui,
+		depsFactory:   depsFactory,
+		logger:        logger,
+		DeployOptions: cmdapp.NewDeployOptions(ui, depsFactory, logger, preflights),
+	}
+}
+
+func NewRollbackCmd(o *RollbackOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Rollback app to resources recorded in app change",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdapp.TTYByDefaultKey: "",
+		},
+		Example: `
+  # Deploy app 'app1' while recording deployed resources in app changes
+  kapp deploy -a app1 -f config/ --app-changes-record-resources
+
+  # Rollback app 'app1' to resources recorded in app change 'app1-change-abcde'
+  kapp app-change rollback -a app1 --to app1-change-abcde`,
+	}
+
+	cmd.SetUsageTemplate(cobrautil.FlagHelpSectionsUsageTemplate([]cobrautil.FlagHelpSection{
+		cmdapp.CommonFlagGroup,
+		cmdapp.DiffFlagGroup,
+		cmdapp.ApplyFlagGroup,
+		cmdapp.WaitFlagGroup,
+		cmdapp.ResourceFilterFlagGroup,
+		cmdapp.ResourceValidationFlagGroup,
+		cmdapp.ResourceManglingFlagGroup,
+		cmdapp.LogsFlagGroup,
+		cmdapp.OtherFlagGroup,
+	}))
+
+	cmd.Flags().StringVar(&o.ToChangeName, "to", "", "Set app change name to rollback to")
+
+	o.DeployOptions.AppFlags.Set(cmd, flagsFactory)
+	o.DeployOptions.DiffFlags.SetWithPrefix("diff", cmd)
+	o.DeployOptions.ResourceFilterFlags.Set(cmd)
+	o.DeployOptions.ApplyFlags.SetWithDefaults("", cmdapp.ApplyFlagsDeployDefaults, cmd)
+	o.DeployOptions.DeployFlags.Set(cmd)
+	o.DeployOptions.ResourceTypesFlags.Set(cmd)
+	o.DeployOptions.PreflightChecks.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *RollbackOptions) Run() error {
+	if len(o.ToChangeName) == 0 {
+		return fmt.Errorf("Expected app change name to be specified via --to")
+	}
+
+	rs, err := o.changeResources()
+	if err != nil {
+		return err
+	}
+
+	o.DeployOptions.ResourcesFunc = func() ([]ctlres.Resource, error) { return rs, nil }
+	// Always record resources so that rollback change can be
+	// rolled back to or diffed against later
+	o.DeployOptions.DeployFlags.AppChangesRecordResources = true
+
+	return o.DeployOptions.Run()
+}
+
+func (o *RollbackOptions) changeResources() ([]ctlres.Resource, error) {
+	app, _, err := cmdapp.Factory(o.depsFactory, o.DeployOptions.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s", notExistsMsg)
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	return recordedChangeResources(changes, o.ToChangeName)
+}
@@ -0,0 +1,10 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+const (
+	// ExtraArgsKey marks leaf commands that accept positional arguments
+	// (arguments are expected to be validated via cobra.Command's Args)
+	ExtraArgsKey = "kapp-extra-args"
+)
@@ -49,16 +49,16 @@ func NewKappOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory,
 func NewDefaultKappCmd(ui *ui.ConfUI) *cobra.Command {
 	configFactory := cmdcore.NewConfigFactoryImpl()
 	depsFactory := cmdcore.NewDepsFactoryImpl(configFactory, ui)
-	preflights := defaultKappPreflightRegistry(depsFactory)
+	preflights := defaultKappPreflightRegistry(depsFactory, ui)
 	options := NewKappOptions(ui, configFactory, depsFactory, preflights)
 	flagsFactory := cmdcore.NewFlagsFactory(configFactory, depsFactory)
 	return NewKappCmd(options, flagsFactory)
 }
 
-func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory) *preflight.Registry {
+func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory, ui ui.UI) *preflight.Registry {
 	registry := preflight.NewRegistry(map[string]preflight.Check{
-		"PermissionValidation": permissions.NewPreflight(depsFactory, false),
-		"CRDUpgradeSafety":     crdupgradesafety.NewPreflight(depsFactory, false),
+		"PermissionValidation": permissions.NewPreflight(depsFactory, ui, false),
+		"CRDUpgradeSafety":     crdupgradesafety.NewPreflight(depsFactory, ui, false),
 	})
 
 	return registry
@@ -110,6 +110,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 
 	cmd.AddCommand(cmdapp.NewListCmd(cmdapp.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewInspectCmd(cmdapp.NewInspectOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewDriftCmd(cmdapp.NewDriftOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployConfigCmd(cmdapp.NewDeployConfigOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
@@ -129,6 +130,8 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	acCmd := cmdac.NewCmd()
 	acCmd.AddCommand(cmdac.NewListCmd(cmdac.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	acCmd.AddCommand(cmdac.NewGCCmd(cmdac.NewGCOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	acCmd.AddCommand(cmdac.NewDiffCmd(cmdac.NewDiffOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	acCmd.AddCommand(cmdac.NewRollbackCmd(cmdac.NewRollbackOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks), flagsFactory))
 	cmd.AddCommand(acCmd)
 
 	saCmd := cmdsa.NewCmd()
@@ -139,6 +142,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	appCmd.AddCommand(cmdtools.NewInspectCmd(cmdtools.NewInspectOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewDiffCmd(cmdtools.NewDiffOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewListLabelsCmd(cmdtools.NewListLabelsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdapp.NewRBACForAppCmd(cmdapp.NewRBACForAppOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(appCmd)
 
 	finishDebugLog := func(cmd *cobra.Command) {
@@ -172,7 +176,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		}))
 	}
 
-	cobrautil.VisitCommands(cmd, cobrautil.ReconfigureLeafCmds(cobrautil.DisallowExtraArgs))
+	cobrautil.VisitCommands(cmd, cobrautil.ReconfigureLeafCmds(disallowExtraArgs))
 
 	// Completion command have to be added after the cobrautil.DisallowExtraArgs.
 	// This due to the ReconfigureLeafCmds that we do not want to have enforced for the completion
@@ -185,6 +189,13 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	return cmd
 }
 
+func disallowExtraArgs(cmd *cobra.Command) {
+	if _, found := cmd.Annotations[cmdcore.ExtraArgsKey]; found {
+		return
+	}
+	cobrautil.DisallowExtraArgs(cmd)
+}
+
 type uiBlockWriter struct {
 	ui ui.UI
 }
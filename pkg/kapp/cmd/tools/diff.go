@@ -12,6 +12,7 @@ import (
 	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldiffui "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffui"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 )
 
@@ -43,6 +44,11 @@ func NewDiffCmd(o *DiffOptions, _ cmdcore.FlagsFactory) *cobra.Command {
 }
 
 func (o *DiffOptions) Run() error {
+	err := o.DiffFlags.ChangeSetViewOpts.Validate()
+	if err != nil {
+		return err
+	}
+
 	newResources, err := o.fileResources(o.FileFlags.Files)
 	if err != nil {
 		return err
@@ -67,7 +73,11 @@ func (o *DiffOptions) Run() error {
 	}
 
 	// TODO support adding custom config for mask rules?
-	ctlcap.NewChangeSetView(changeViews, nil, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
+	ctlcap.NewChangeSetView(changeViews, nil, nil, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
+
+	if len(o.DiffFlags.HTMLReport) > 0 {
+		return ctldiffui.NewHTMLReport(changeViews, nil, nil, o.DiffFlags.TextDiffViewOpts).WriteToFile(o.DiffFlags.HTMLReport)
+	}
 
 	return nil
 }
@@ -100,6 +110,10 @@ type DiffChangeView struct {
 
 var _ ctlcap.ChangeView = DiffChangeView{}
 
+func NewDiffChangeView(change ctldiff.Change) DiffChangeView {
+	return DiffChangeView{change}
+}
+
 func (v DiffChangeView) Resource() ctlres.Resource { return v.change.NewOrExistingResource() }
 
 func (v DiffChangeView) ClusterOriginalResource() ctlres.Resource {
@@ -131,3 +145,10 @@ func (v DiffChangeView) WaitOp() ctlcap.ClusterChangeWaitOp { return ctlcap.Clus
 func (v DiffChangeView) ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff {
 	return v.change.ConfigurableTextDiff()
 }
+
+// Since we are diffing changes without a cluster, there is no field ownership
+func (v DiffChangeView) FieldOwnershipConflicts() (ctlcap.FieldOwnershipConflicts, error) {
+	return nil, nil
+}
+
+func (v DiffChangeView) DryRunResult() *ctlcap.DryRunResult { return nil }
@@ -17,6 +17,8 @@ type DiffFlags struct {
 	Run        bool
 	ExitStatus bool
 	UI         bool
+	UILive     bool
+	HTMLReport string
 
 	AnchoredDiff bool
 }
@@ -29,10 +31,15 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&s.Run, prefix+"run", false, "Show diff and exit successfully without any further action")
 	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"exit-status", false, "Return specific exit status based on number of changes")
 	cmd.Flags().BoolVar(&s.UI, prefix+"ui-alpha", false, "Start UI server to inspect changes (alpha feature)")
+	cmd.Flags().BoolVar(&s.UILive, prefix+"ui-live-alpha", false, "Start UI server to inspect changes and follow apply progress (alpha feature)")
+	cmd.Flags().StringVar(&s.HTMLReport, prefix+"html-report", "", "Write changes into self-contained HTML report at given path")
 
 	cmd.Flags().BoolVar(&s.Summary, prefix+"summary", true, "Show diff summary")
 	cmd.Flags().BoolVarP(&s.Changes, prefix+"changes", "c", false, "Show changes")
 
+	cmd.Flags().StringVar(&s.Format, prefix+"format", ctlcap.ChangeSetViewFormatText,
+		"Set format of shown changes (text, json, unified, json-patch, semantic)")
+
 	cmd.Flags().IntVar(&s.Context, prefix+"context", 2, "Show number of lines around changed lines")
 	cmd.Flags().BoolVar(&s.LineNumbers, prefix+"line-numbers", true, "Show line numbers")
 	cmd.Flags().BoolVar(&s.Mask, prefix+"mask", true, "Apply masking rules")
@@ -41,6 +48,8 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&s.Filter, prefix+"filter", "", `Set changes filter (example: {"and":[{"ops":["update"]},{"existingResource":{"kinds":["Deployment"]}]})`)
 	cmd.Flags().BoolVar(&s.ChangesYAML, prefix+"changes-yaml", false, "Print YAML to be applied")
+	cmd.Flags().BoolVar(&s.FieldOwnershipConflicts, prefix+"field-ownership-conflicts", false,
+		"Show field ownership conflicts for server-side apply changes (makes dry run request per change)")
 
 	cmd.Flags().BoolVar(&s.AnchoredDiff, prefix+"anchored", false, "Allow using anchored diff for large resources")
 }
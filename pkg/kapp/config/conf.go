@@ -56,6 +56,19 @@ func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf,
 	return rsWithoutConfigs, Conf{configs}, nil
 }
 
+// IsConfigResource returns true if resource would be
+// interpreted as kapp config by NewConfFromResources
+func IsConfigResource(res ctlres.Resource) bool {
+	_, isLabeledAsConfig := res.Labels()[configLabelKey]
+	return res.APIVersion() == configAPIVersion || isLabeledAsConfig
+}
+
+// IsConfigKindResource returns true only for kapp Config resources
+// (unlike ConfigMaps labeled as kapp config, they are not deployed)
+func IsConfigKindResource(res ctlres.Resource) bool {
+	return res.APIVersion() == configAPIVersion && res.Kind() == configKind
+}
+
 func newConfigFromConfigMapRes(res ctlres.Resource) (Config, error) {
 	if res.APIVersion() != "v1" || res.Kind() != "ConfigMap" {
 		errMsg := "Expected kapp config to be within v1/ConfigMap but apiVersion or kind do not match"
@@ -171,6 +184,22 @@ func (c Conf) DiffMaskRules() []DiffMaskRule {
 	return result
 }
 
+func (c Conf) DiffListMergeKeyRules() []DiffListMergeKeyRule {
+	var result []DiffListMergeKeyRule
+	for _, config := range c.configs {
+		result = append(result, config.DiffListMergeKeyRules...)
+	}
+	return result
+}
+
+func (c Conf) ConfirmationRules() []ConfirmationRule {
+	var result []ConfirmationRule
+	for _, config := range c.configs {
+		result = append(result, config.ConfirmationRules...)
+	}
+	return result
+}
+
 func (c Conf) AdditionalLabels() map[string]string {
 	result := map[string]string{}
 	for _, config := range c.configs {
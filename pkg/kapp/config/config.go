@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	semver "github.com/hashicorp/go-version"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
@@ -32,6 +33,9 @@ type Config struct {
 	DiffMaskRules       []DiffMaskRule
 	PreflightRules      []PreflightRule
 
+	DiffListMergeKeyRules []DiffListMergeKeyRule
+	ConfirmationRules     []ConfirmationRule
+
 	AdditionalLabels                          map[string]string
 	DiffAgainstLastAppliedFieldExclusionRules []DiffAgainstLastAppliedFieldExclusionRule
 	DiffAgainstExistingFieldExclusionRules    []DiffAgainstExistingFieldExclusionRule
@@ -47,8 +51,24 @@ type WaitRule struct {
 	ConditionMatchers          []WaitRuleConditionMatcher
 	ResourceMatchers           []ResourceMatcher
 	Ytt                        *WaitRuleYtt
+	CEL                        *WaitRuleCEL `json:"cel"`
+	// Associated resources are provided to ytt and cel wait rules
+	AssociatedResources []WaitRuleAssociatedResource `json:"associatedResources"`
+
+	// Timeout overrides resource wait timeout (e.g. 20m) for matching resources.
+	// Rules that only specify timeout do not affect how resource state is determined.
+	Timeout       string `json:"timeout"`
+	TimeoutAction string `json:"timeoutAction"`
 }
 
+const (
+	// WaitRuleTimeoutActionFail fails deploy when resource times out (default)
+	WaitRuleTimeoutActionFail = "fail"
+	// WaitRuleTimeoutActionWarn considers timed out resource as done
+	// and reports a warning instead of failing deploy
+	WaitRuleTimeoutActionWarn = "warn"
+)
+
 type WaitRuleConditionMatcher struct {
 	Type                       string
 	Status                     string
@@ -124,6 +144,29 @@ type DiffMaskRule struct {
 	Path             ctlres.Path
 }
 
+// DiffListMergeKeyRule specifies fields that identify items of a list
+// so that semantic diff matches items regardless of their order
+type DiffListMergeKeyRule struct {
+	ResourceMatchers []ResourceMatcher
+	Path             ctlres.Path
+	MergeKeys        []string `json:"mergeKeys"`
+}
+
+// ConfirmationRule marks matching changes as dangerous so that they
+// have to be explicitly confirmed (by typing rule name) before being applied
+type ConfirmationRule struct {
+	Name             string
+	ResourceMatchers []ResourceMatcher
+	// Ops limits rule to changes with given ops (default: create, update and delete)
+	Ops []string
+}
+
+const (
+	ConfirmationRuleOpCreate = "create"
+	ConfirmationRuleOpUpdate = "update"
+	ConfirmationRuleOpDelete = "delete"
+)
+
 type TemplateAffectedResources struct {
 	ObjectReferences []TemplateAffectedObjRef
 	// TODO support label injections?
@@ -149,6 +192,21 @@ type ChangeRuleBinding struct {
 type PreflightRule struct {
 	Name   string
 	Config map[string]any
+
+	// Following fields are used by custom (user defined) preflight rules
+	// which are evaluated against each change
+	ResourceMatchers []ResourceMatcher
+	Ytt              *PreflightRuleYtt
+}
+
+type PreflightRuleYtt struct {
+	// Contracts are named and versioned (eg v1)
+	// to provide a stable interface to rule authors.
+	FuncContractV1 *PreflightRuleFuncContractV1 `json:"funcContractV1"`
+}
+
+type PreflightRuleFuncContractV1 struct {
+	Rule string `json:"rule.star"`
 }
 
 func NewConfigFromResource(res ctlres.Resource) (Config, error) {
@@ -223,6 +281,40 @@ func (c Config) Validate() error {
 		}
 	}
 
+	for i, rule := range c.WaitRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating wait rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.PreflightRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating preflight rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.DiffListMergeKeyRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating diff list merge key rule %d: %w", i, err)
+		}
+	}
+
+	confirmationRuleNames := map[string]struct{}{}
+
+	for i, rule := range c.ConfirmationRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating confirmation rule %d: %w", i, err)
+		}
+		if _, found := confirmationRuleNames[rule.Name]; found {
+			return fmt.Errorf("Validating confirmation rule %d: Expected name '%s' to be unique", i, rule.Name)
+		}
+		confirmationRuleNames[rule.Name] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -242,6 +334,96 @@ func (r RebaseRule) Validate() error {
 	return nil
 }
 
+func (r PreflightRule) Validate() error {
+	if r.Ytt != nil {
+		if len(r.Name) == 0 {
+			return fmt.Errorf("Expected name to be specified with ytt configuration")
+		}
+		if r.Ytt.FuncContractV1 == nil {
+			return fmt.Errorf("Expected ytt configuration to specify contract (supported: funcContractV1)")
+		}
+		if len(r.Config) > 0 {
+			return fmt.Errorf("Expected config to not be specified with ytt configuration")
+		}
+		return nil
+	}
+	if len(r.ResourceMatchers) > 0 {
+		return fmt.Errorf("Expected resourceMatchers to only be specified with ytt configuration")
+	}
+	return nil
+}
+
+func (r DiffListMergeKeyRule) Validate() error {
+	if len(r.Path) == 0 {
+		return fmt.Errorf("Expected path to be specified")
+	}
+	for _, part := range r.Path {
+		if part.Regex != nil {
+			return fmt.Errorf("Expected path to not contain regex parts")
+		}
+	}
+	if len(r.MergeKeys) == 0 {
+		return fmt.Errorf("Expected at least one merge key to be specified")
+	}
+	return nil
+}
+
+func (r ConfirmationRule) Validate() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("Expected name to be specified")
+	}
+	for _, op := range r.Ops {
+		switch op {
+		case ConfirmationRuleOpCreate, ConfirmationRuleOpUpdate, ConfirmationRuleOpDelete:
+		default:
+			return fmt.Errorf("Expected op to be one of: %s, %s, %s (given: '%s')",
+				ConfirmationRuleOpCreate, ConfirmationRuleOpUpdate, ConfirmationRuleOpDelete, op)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if change with given op (create, update, delete, etc.)
+// on given resource requires confirmation. Rule without resource matchers matches all resources.
+func (r ConfirmationRule) Matches(res ctlres.Resource, op string) bool {
+	ops := r.Ops
+	if len(ops) == 0 {
+		ops = []string{ConfirmationRuleOpCreate, ConfirmationRuleOpUpdate, ConfirmationRuleOpDelete}
+	}
+
+	var opMatched bool
+	for _, ruleOp := range ops {
+		if ruleOp == op {
+			opMatched = true
+			break
+		}
+	}
+	if !opMatched {
+		return false
+	}
+
+	if len(r.ResourceMatchers) == 0 {
+		return true
+	}
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}.Matches(res)
+}
+
+// IsCustom returns true if rule is defined by the user
+// instead of configuring one of built-in preflight checks
+func (r PreflightRule) IsCustom() bool { return r.Ytt != nil }
+
+// ResourceMatcher matches all resources if rule does not specify any matchers
+func (r PreflightRule) ResourceMatcher() ctlres.ResourceMatcher {
+	if len(r.ResourceMatchers) == 0 {
+		return ctlres.AllMatcher{}
+	}
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
 func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 	if r.Ytt != nil {
 		switch {
@@ -332,6 +514,43 @@ func (r LabelScopingRule) AsMod(kvs map[string]string) ctlres.StringMapAppendMod
 	}
 }
 
+func (r WaitRule) Validate() error {
+	if len(r.Timeout) > 0 {
+		_, err := time.ParseDuration(r.Timeout)
+		if err != nil {
+			return fmt.Errorf("Parsing timeout: %w", err)
+		}
+	}
+	switch r.TimeoutAction {
+	case "", WaitRuleTimeoutActionFail, WaitRuleTimeoutActionWarn:
+	default:
+		return fmt.Errorf("Expected timeoutAction to be one of: %s, %s (given: '%s')",
+			WaitRuleTimeoutActionFail, WaitRuleTimeoutActionWarn, r.TimeoutAction)
+	}
+	for i, assocRes := range r.AssociatedResources {
+		err := assocRes.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating associated resource %d: %w", i, err)
+		}
+	}
+	if len(r.AssociatedResources) > 0 && r.Ytt == nil && r.CEL == nil {
+		return fmt.Errorf("Expected associatedResources to be used with ytt or cel")
+	}
+	if r.CEL != nil {
+		if r.Ytt != nil || len(r.ConditionMatchers) > 0 {
+			return fmt.Errorf("Expected only one of ytt, cel or conditionMatchers specified")
+		}
+		return r.CEL.Validate()
+	}
+	return nil
+}
+
+// OnlyConfiguresTimeout indicates that rule does not specify how to determine resource state
+func (r WaitRule) OnlyConfiguresTimeout() bool {
+	return !r.SupportsObservedGeneration && len(r.ConditionMatchers) == 0 &&
+		r.Ytt == nil && r.CEL == nil && (len(r.Timeout) > 0 || len(r.TimeoutAction) > 0)
+}
+
 func (r WaitRule) ResourceMatcher() ctlres.ResourceMatcher {
 	return ctlres.AnyMatcher{
 		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
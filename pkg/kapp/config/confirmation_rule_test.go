@@ -0,0 +1,111 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestConfirmationRuleMatches(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+confirmationRules:
+- name: delete-namespaces
+  ops: [delete]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Namespace}
+- name: unprotected-in-prod
+  resourceMatchers:
+  - andMatcher:
+      matchers:
+      - hasNamespaceMatcher: {names: [prod]}
+      - notMatcher:
+          matcher:
+            hasAnnotationMatcher: {keys: [kapp.k14s.io/delete-strategy]}
+`))
+
+	cfg, err := config.NewConfigFromResource(configRes)
+	require.NoError(t, err)
+	require.Len(t, cfg.ConfirmationRules, 2)
+
+	nsRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+`))
+
+	prodRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: prod
+`))
+
+	protectedProdRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: prod
+  annotations:
+    kapp.k14s.io/delete-strategy: orphan
+`))
+
+	nsRule, prodRule := cfg.ConfirmationRules[0], cfg.ConfirmationRules[1]
+
+	require.True(t, nsRule.Matches(nsRes, "delete"))
+	require.False(t, nsRule.Matches(nsRes, "update"))
+	require.False(t, nsRule.Matches(prodRes, "delete"))
+
+	require.True(t, prodRule.Matches(prodRes, "create"))
+	require.True(t, prodRule.Matches(prodRes, "delete"))
+	require.False(t, prodRule.Matches(prodRes, "noop"))
+	require.False(t, prodRule.Matches(protectedProdRes, "delete"))
+	require.False(t, prodRule.Matches(nsRes, "delete"))
+}
+
+func TestConfirmationRuleValidation(t *testing.T) {
+	testCases := []struct {
+		description string
+		rules       string
+		expectedErr string
+	}{
+		{
+			description: "missing name",
+			rules:       `- ops: [delete]`,
+			expectedErr: "Validating confirmation rule 0: Expected name to be specified",
+		},
+		{
+			description: "unknown op",
+			rules:       `- {name: rule, ops: [remove]}`,
+			expectedErr: "Validating confirmation rule 0: Expected op to be one of: create, update, delete (given: 'remove')",
+		},
+		{
+			description: "duplicate name",
+			rules:       "- {name: rule}\n- {name: rule}",
+			expectedErr: "Validating confirmation rule 1: Expected name 'rule' to be unique",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+confirmationRules:
+` + tc.rules))
+
+			_, err := config.NewConfigFromResource(configRes)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WaitRuleAssociatedResource declares resources (e.g. created by an operator)
+// whose state should be available to a wait rule. Resources are found
+// by owner reference, label selector or name template. Label selector and
+// name template may refer to waited resource via $(name) and $(namespace).
+type WaitRuleAssociatedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string
+
+	OwnerReference bool   `json:"ownerReference"`
+	LabelSelector  string `json:"labelSelector"`
+	NameTemplate   string `json:"nameTemplate"`
+}
+
+func (r WaitRuleAssociatedResource) Validate() error {
+	if len(r.APIVersion) == 0 || len(r.Kind) == 0 {
+		return fmt.Errorf("Expected apiVersion and kind to be specified")
+	}
+
+	var specified int
+	for _, val := range []bool{r.OwnerReference, len(r.LabelSelector) > 0, len(r.NameTemplate) > 0} {
+		if val {
+			specified++
+		}
+	}
+	if specified != 1 {
+		return fmt.Errorf("Expected exactly one of ownerReference, labelSelector or nameTemplate specified")
+	}
+
+	if len(r.LabelSelector) > 0 {
+		_, err := labels.Parse(r.expand(r.LabelSelector, "name", "namespace"))
+		if err != nil {
+			return fmt.Errorf("Parsing label selector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AsQuery returns query for resources associated with given resource
+func (r WaitRuleAssociatedResource) AsQuery(res ctlres.Resource) (ctlres.AssociatedResourcesQuery, error) {
+	gv, err := schema.ParseGroupVersion(r.APIVersion)
+	if err != nil {
+		return ctlres.AssociatedResourcesQuery{}, err
+	}
+
+	query := ctlres.AssociatedResourcesQuery{
+		GroupKind:      schema.GroupKind{Group: gv.Group, Kind: r.Kind},
+		OwnerReference: r.OwnerReference,
+	}
+
+	if len(r.LabelSelector) > 0 {
+		query.LabelSelector, err = labels.Parse(r.expand(r.LabelSelector, res.Name(), res.Namespace()))
+		if err != nil {
+			return ctlres.AssociatedResourcesQuery{}, fmt.Errorf("Parsing label selector: %w", err)
+		}
+	}
+
+	if len(r.NameTemplate) > 0 {
+		query.Name = r.expand(r.NameTemplate, res.Name(), res.Namespace())
+	}
+
+	return query, nil
+}
+
+func (WaitRuleAssociatedResource) expand(tpl, name, namespace string) string {
+	return strings.NewReplacer("$(name)", name, "$(namespace)", namespace).Replace(tpl)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+)
+
+const (
+	// WaitRuleCELResourceVar holds resource being waited on
+	WaitRuleCELResourceVar = "resource"
+	// WaitRuleCELAssociatedResourcesVar holds associated resources
+	// (e.g. Pods of a Deployment) that were found for the resource
+	WaitRuleCELAssociatedResourcesVar = "associatedResources"
+)
+
+// WaitRuleCEL specifies CEL expressions that evaluate to bool.
+// Failure expression is checked first, then success and in progress.
+type WaitRuleCEL struct {
+	Success    string `json:"success"`
+	Failure    string `json:"failure"`
+	InProgress string `json:"inProgress"`
+}
+
+// WaitRuleCELPrograms holds compiled expressions
+// (nil if corresponding expression was not specified)
+type WaitRuleCELPrograms struct {
+	Success    cel.Program
+	Failure    cel.Program
+	InProgress cel.Program
+}
+
+func (r WaitRuleCEL) Validate() error {
+	if len(r.Success) == 0 && len(r.Failure) == 0 {
+		return fmt.Errorf("Expected at least one of cel success or failure expressions specified")
+	}
+	_, err := r.Programs()
+	return err
+}
+
+// Programs compiles expressions. Compilation is relatively expensive,
+// hence it is expected to be done once and programs reused for evaluation.
+func (r WaitRuleCEL) Programs() (WaitRuleCELPrograms, error) {
+	env, err := cel.NewEnv(
+		cel.Variable(WaitRuleCELResourceVar, cel.DynType),
+		cel.Variable(WaitRuleCELAssociatedResourcesVar, cel.ListType(cel.DynType)),
+	)
+	if err != nil {
+		return WaitRuleCELPrograms{}, fmt.Errorf("Building cel environment: %w", err)
+	}
+
+	var programs WaitRuleCELPrograms
+
+	exprs := []struct {
+		Name    string
+		Expr    string
+		Program *cel.Program
+	}{
+		{"success", r.Success, &programs.Success},
+		{"failure", r.Failure, &programs.Failure},
+		{"inProgress", r.InProgress, &programs.InProgress},
+	}
+
+	for _, expr := range exprs {
+		if len(expr.Expr) == 0 {
+			continue
+		}
+
+		ast, issues := env.Compile(expr.Expr)
+		if issues != nil && issues.Err() != nil {
+			return WaitRuleCELPrograms{}, fmt.Errorf("Compiling cel %s expression: %w", expr.Name, issues.Err())
+		}
+
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return WaitRuleCELPrograms{}, fmt.Errorf("Expected cel %s expression to evaluate to bool, but was %s",
+				expr.Name, ast.OutputType())
+		}
+
+		*expr.Program, err = env.Program(ast)
+		if err != nil {
+			return WaitRuleCELPrograms{}, fmt.Errorf("Building cel %s expression: %w", expr.Name, err)
+		}
+	}
+
+	return programs, nil
+}
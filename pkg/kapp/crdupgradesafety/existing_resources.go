@@ -0,0 +1,280 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const (
+	// NoInvalidExistingResources is a name of validation that checks
+	// existing custom resources against the new CRD schema
+	NoInvalidExistingResources = "NoInvalidExistingResources"
+
+	ExistingResourcesSampleSizeDefault = 100
+)
+
+// ExistingResourcesValidator lists existing custom resources of the CRD
+// being upgraded and validates them against schemas of the new CRD.
+// Only a sample of resources is validated to bound time spent for large populations.
+type ExistingResourcesValidator struct {
+	DynamicClient dynamic.Interface
+	// SampleSize is a maximum number of resources validated per version
+	SampleSize int64
+}
+
+func (v ExistingResourcesValidator) Validate(ctx context.Context, old, new v1.CustomResourceDefinition) error {
+	oldVersions := map[string]v1.CustomResourceDefinitionVersion{}
+	for _, version := range old.Spec.Versions {
+		oldVersions[version.Name] = version
+	}
+
+	var errs []error
+
+	for _, newVersion := range new.Spec.Versions {
+		// Only versions that are currently served could have resources listed
+		oldVersion, found := oldVersions[newVersion.Name]
+		if !found || !oldVersion.Served || !newVersion.Served {
+			continue
+		}
+		if newVersion.Schema == nil || newVersion.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		validator, err := newSchemaObjectValidator(newVersion.Schema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("version %q: building schema validator: %w", newVersion.Name, err))
+			continue
+		}
+
+		gvr := schema.GroupVersionResource{Group: new.Spec.Group, Version: newVersion.Name, Resource: new.Spec.Names.Plural}
+
+		// Listing may fail for a single version (e.g. due to failing conversion webhook)
+		// hence report it for that version and continue with other versions
+		list, err := v.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: v.SampleSize})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("version %q: listing existing resources: %w", newVersion.Name, err))
+			continue
+		}
+
+		for _, item := range list.Items {
+			fieldErrs := validator.Validate(ctx, item.Object)
+			if len(fieldErrs) > 0 {
+				desc := item.GetName()
+				if len(item.GetNamespace()) > 0 {
+					desc = item.GetNamespace() + "/" + desc
+				}
+				errs = append(errs, fmt.Errorf("version %q: resource %q would fail validation: %w",
+					newVersion.Name, desc, fieldErrs.ToAggregate()))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// schemaObjectValidator validates objects the same way API server
+// validates custom resources against OpenAPI schema (including formats,
+// combinators, etc.). Rules in x-kubernetes-validations (CEL) are not
+// evaluated since API server's CEL validator depends on API server feature
+// gates and registers global metrics on init (API server still enforces
+// them once resources are updated).
+type schemaObjectValidator struct {
+	structural      *structuralschema.Structural
+	schemaValidator *validate.SchemaValidator
+}
+
+func newSchemaObjectValidator(validation *v1.CustomResourceValidation) (*schemaObjectValidator, error) {
+	internalValidation := &apiextensions.CustomResourceValidation{}
+
+	err := v1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(validation, internalValidation, nil)
+	if err != nil {
+		return nil, fmt.Errorf("converting schema: %w", err)
+	}
+
+	structural, err := structuralschema.NewStructural(internalValidation.OpenAPIV3Schema)
+	if err != nil {
+		return nil, fmt.Errorf("building structural schema: %w", err)
+	}
+
+	openapiSchema := structural.ToKubeOpenAPI()
+	stripUnsupportedFormats(openapiSchema)
+
+	return &schemaObjectValidator{
+		structural:      structural,
+		schemaValidator: validate.NewSchemaValidator(openapiSchema, nil, "", strfmt.Default),
+	}, nil
+}
+
+func (v *schemaObjectValidator) Validate(_ context.Context, obj map[string]interface{}) field.ErrorList {
+	obj = runtime.DeepCopyJSON(obj)
+
+	// Objects are read from API server with defaults of the new schema applied
+	applyDefaults(obj, v.structural)
+
+	result := v.schemaValidator.Validate(obj)
+	if result.IsValid() {
+		return nil
+	}
+
+	return validationResultToFieldErrors(result)
+}
+
+// supportedFormats are formats validated by API server
+// (others are ignored, same as in apiextensions-apiserver validation package)
+var supportedFormats = sets.New[string](
+	"bsonobjectid", "uri", "email", "hostname", "ipv4", "ipv6", "cidr", "mac",
+	"uuid", "uuid3", "uuid4", "uuid5", "isbn", "isbn10", "isbn13", "creditcard",
+	"ssn", "hexcolor", "rgbcolor", "byte", "password", "date", "duration", "datetime",
+)
+
+func stripUnsupportedFormats(s *spec.Schema) {
+	if s == nil {
+		return
+	}
+
+	// Same format name normalization as done by go-openapi
+	if len(s.Format) > 0 && !supportedFormats.Has(strings.ReplaceAll(s.Format, "-", "")) {
+		s.Format = ""
+	}
+
+	for k, prop := range s.Properties {
+		stripUnsupportedFormats(&prop)
+		s.Properties[k] = prop
+	}
+	if s.Items != nil {
+		stripUnsupportedFormats(s.Items.Schema)
+	}
+	if s.AdditionalProperties != nil {
+		stripUnsupportedFormats(s.AdditionalProperties.Schema)
+	}
+	for _, schemas := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range schemas {
+			stripUnsupportedFormats(&schemas[i])
+		}
+	}
+	stripUnsupportedFormats(s.Not)
+}
+
+// applyDefaults sets default values from schema for missing
+// (or non-nullable null) fields same as API server does
+func applyDefaults(x interface{}, s *structuralschema.Structural) {
+	if s == nil {
+		return
+	}
+
+	isNonNullableNull := func(x interface{}, s *structuralschema.Structural) bool {
+		return x == nil && s != nil && !s.Generic.Nullable
+	}
+
+	switch x := x.(type) {
+	case map[string]interface{}:
+		for k, prop := range s.Properties {
+			if prop.Default.Object == nil {
+				continue
+			}
+			if _, found := x[k]; !found || isNonNullableNull(x[k], &prop) {
+				x[k] = runtime.DeepCopyJSONValue(prop.Default.Object)
+			}
+		}
+		for k := range x {
+			if prop, found := s.Properties[k]; found {
+				applyDefaults(x[k], &prop)
+			} else if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+				if isNonNullableNull(x[k], s.AdditionalProperties.Structural) {
+					x[k] = runtime.DeepCopyJSONValue(s.AdditionalProperties.Structural.Default.Object)
+				}
+				applyDefaults(x[k], s.AdditionalProperties.Structural)
+			}
+		}
+	case []interface{}:
+		for i := range x {
+			if isNonNullableNull(x[i], s.Items) {
+				x[i] = runtime.DeepCopyJSONValue(s.Items.Default.Object)
+			}
+			applyDefaults(x[i], s.Items)
+		}
+	}
+}
+
+// validationResultToFieldErrors converts OpenAPI validation errors
+// to field errors formatted the same way API server formats them
+func validationResultToFieldErrors(result *validate.Result) field.ErrorList {
+	var errs field.ErrorList
+
+	for _, err := range result.Errors {
+		validationErr, ok := err.(*openapierrors.Validation)
+		if !ok {
+			errs = append(errs, field.Invalid(nil, "", err.Error()))
+			continue
+		}
+
+		var path *field.Path
+		if len(validationErr.Name) > 0 && validationErr.Name != "." {
+			path = path.Child(strings.TrimPrefix(validationErr.Name, "."))
+		}
+
+		value := validationErr.Value
+		if value == nil {
+			value = ""
+		}
+
+		switch validationErr.Code() {
+		case openapierrors.RequiredFailCode:
+			errs = append(errs, field.Required(path, ""))
+
+		case openapierrors.EnumFailCode:
+			var values []string
+			for _, allowedValue := range validationErr.Values {
+				if str, ok := allowedValue.(string); ok {
+					values = append(values, str)
+				} else {
+					allowedJSON, _ := json.Marshal(allowedValue)
+					values = append(values, string(allowedJSON))
+				}
+			}
+			errs = append(errs, field.NotSupported(path, validationErr.Value, values))
+
+		case openapierrors.TooLongFailCode:
+			errs = append(errs, field.TooLongMaxLength(path, value, int(int64OrNegative(validationErr.Valid))))
+
+		case openapierrors.MaxItemsFailCode, openapierrors.TooManyPropertiesCode:
+			errs = append(errs, field.TooMany(path, int(int64OrNegative(validationErr.Value)),
+				int(int64OrNegative(validationErr.Valid))))
+
+		case openapierrors.InvalidTypeCode:
+			errs = append(errs, field.TypeInvalid(path, value, validationErr.Error()))
+
+		default:
+			errs = append(errs, field.Invalid(path, value, validationErr.Error()))
+		}
+	}
+
+	return errs
+}
+
+func int64OrNegative(val interface{}) int64 {
+	if i, ok := val.(int64); ok {
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestSchemaObjectValidator(t *testing.T) {
+	int64Ptr := func(val int64) *int64 { return &val }
+	float64Ptr := func(val float64) *float64 { return &val }
+
+	validator, err := newSchemaObjectValidator(&v1.CustomResourceValidation{
+		OpenAPIV3Schema: &v1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]v1.JSONSchemaProps{
+				"spec": {
+					Type:     "object",
+					Required: []string{"size"},
+					Properties: map[string]v1.JSONSchemaProps{
+						"size":        {Type: "string", Enum: []v1.JSON{{Raw: []byte(`"small"`)}, {Raw: []byte(`"large"`)}}},
+						"replicas":    {Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(5)},
+						"maxReplicas": {Type: "integer", Default: &v1.JSON{Raw: []byte(`5`)}},
+						"name":        {Type: "string", MaxLength: int64Ptr(5), Pattern: "^[a-z]+$"},
+						"email":       {Type: "string", Format: "email"},
+						"zone":        {Type: "string", Format: "unsupported-format"},
+						"step":        {Type: "integer", MultipleOf: float64Ptr(2)},
+						"tags": {
+							Type:     "array",
+							MaxItems: int64Ptr(2),
+							Items:    &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{Type: "string"}},
+						},
+					},
+					// Rules are not evaluated (only enforced by API server)
+					XValidations: v1.ValidationRules{{
+						Rule:    "!has(self.replicas) || self.replicas <= self.maxReplicas",
+						Message: "replicas must not exceed maxReplicas",
+					}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name         string
+		obj          map[string]interface{}
+		expectedErrs []string
+	}{
+		{
+			name: "valid resource, no errors",
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"size":     "small",
+					"replicas": int64(3),
+					"name":     "abc",
+					"email":    "foo@example.com",
+					"zone":     "us-east",
+					"step":     int64(4),
+					"tags":     []interface{}{"a"},
+					"unknown":  true,
+				},
+			},
+		},
+		{
+			name: "invalid resource, errors for each field",
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(10),
+					"name":     "abcdef1",
+					"email":    "not-email",
+					"step":     int64(3),
+					"tags":     []interface{}{"a", "b", "c"},
+				},
+			},
+			expectedErrs: []string{
+				`spec.size: Required value`,
+				`spec.name: Too long: may not be longer than 5`,
+				`spec.email: Invalid value: "not-email": spec.email in body must be of type email: "not-email"`,
+				`spec.replicas: Invalid value: 10: spec.replicas in body should be less than or equal to 5`,
+				`spec.step: Invalid value: 3: spec.step in body should be a multiple of 2`,
+				`spec.tags: Too many: 3: must have at most 2 items`,
+			},
+		},
+		{
+			name: "CEL rule violated, no errors",
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{"size": "small", "replicas": int64(4), "maxReplicas": int64(2)},
+			},
+		},
+		{
+			name: "value not in enum, error",
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{"size": "medium"},
+			},
+			expectedErrs: []string{`spec.size: Unsupported value: "medium": supported values: "small", "large"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validator.Validate(context.Background(), tc.obj)
+
+			var errStrs []string
+			for _, err := range errs {
+				errStrs = append(errStrs, err.Error())
+			}
+			require.ElementsMatch(t, tc.expectedErrs, errStrs, "Errors: %v", errs.ToAggregate())
+		})
+	}
+}
+
+func TestSchemaObjectValidatorDefaults(t *testing.T) {
+	validator, err := newSchemaObjectValidator(&v1.CustomResourceValidation{
+		OpenAPIV3Schema: &v1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]v1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]v1.JSONSchemaProps{
+						"mode": {Type: "string", Default: &v1.JSON{Raw: []byte(`"fast"`)}},
+						"items": {
+							Type: "array",
+							Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{
+								Type:     "object",
+								Required: []string{"name"},
+								Properties: map[string]v1.JSONSchemaProps{
+									"name": {Type: "string", Default: &v1.JSON{Raw: []byte(`"default"`)}},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{"items": []interface{}{map[string]interface{}{}}},
+	}
+
+	// Required field is satisfied by its default
+	require.Empty(t, validator.Validate(context.Background(), obj))
+
+	// Given object is not modified
+	require.Equal(t, map[string]interface{}{
+		"spec": map[string]interface{}{"items": []interface{}{map[string]interface{}{}}},
+	}, obj)
+
+	applyDefaults(obj, validator.structural)
+	require.Equal(t, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"mode":  "fast",
+			"items": []interface{}{map[string]interface{}{"name": "default"}},
+		},
+	}, obj)
+}
+
+func TestPreflightSetConfigExistingResourcesSampleSize(t *testing.T) {
+	p := NewPreflight(nil, nil, true)
+	require.Equal(t, int64(ExistingResourcesSampleSizeDefault), p.existingResourcesSampleSize)
+
+	err := p.SetConfig(map[string]any{"existingResourcesSampleSize": float64(500)})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), p.existingResourcesSampleSize)
+
+	err = p.SetConfig(map[string]any{"existingResourcesSampleSize": float64(0)})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), p.existingResourcesSampleSize)
+
+	err = p.SetConfig(map[string]any{"existingResourcesSampleSize": "all"})
+	require.ErrorContains(t, err, "to be a non-negative integer")
+
+	err = p.SetConfig(map[string]any{"validations": map[string]any{NoInvalidExistingResources: "warn"}})
+	require.NoError(t, err)
+	require.True(t, p.validator.WarnValidations.Has(NoInvalidExistingResources))
+}
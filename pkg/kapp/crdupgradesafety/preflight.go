@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/preflight"
@@ -15,6 +17,15 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	validationsConfigKey                 = "validations"
+	existingResourcesSampleSizeConfigKey = "existingResourcesSampleSize"
+
+	ValidationActionError = "error"
+	ValidationActionWarn  = "warn"
 )
 
 var _ preflight.Check = (*Preflight)(nil)
@@ -24,18 +35,33 @@ var _ preflight.Check = (*Preflight)(nil)
 // as a preflight check
 type Preflight struct {
 	depsFactory cmdcore.DepsFactory
+	ui          ui.UI
 	enabled     bool
 	validator   *Validator
+
+	// existingResourcesSampleSize is a maximum number of existing custom resources
+	// validated against new CRD schema per version (0 disables validation)
+	existingResourcesSampleSize int64
 }
 
-func NewPreflight(df cmdcore.DepsFactory, enabled bool) *Preflight {
+func NewPreflight(df cmdcore.DepsFactory, ui ui.UI, enabled bool) *Preflight {
 	return &Preflight{
 		depsFactory: df,
+		ui:          ui,
 		enabled:     enabled,
+
+		existingResourcesSampleSize: ExistingResourcesSampleSizeDefault,
+
 		validator: &Validator{
 			Validations: []Validation{
 				NewValidationFunc("NoScopeChange", NoScopeChange),
 				NewValidationFunc("NoStoredVersionRemoved", NoStoredVersionRemoved),
+				NewValidationFunc("NoExistingFieldRemoved", NoExistingFieldRemoved),
+				NewFieldValidationFunc("NoRequiredFieldAdded", NoRequiredFieldAdded),
+				NewFieldValidationFunc("NoEnumNarrowed", NoEnumNarrowed),
+				NewFieldValidationFunc("NoConstraintTightened", NoConstraintTightened),
+				NewFieldValidationFunc("NoTypeChange", NoTypeChange),
+				NewFieldValidationFunc("NoDefaultValueChange", NoDefaultValueChange),
 			},
 		},
 	}
@@ -49,7 +75,57 @@ func (p *Preflight) SetEnabled(enabled bool) {
 	p.enabled = enabled
 }
 
-func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+// SetConfig configures action taken for each failed validation
+// and number of existing custom resources to validate, e.g.
+//
+//	config:
+//	  existingResourcesSampleSize: 500
+//	  validations:
+//	    NoDefaultValueChange: warn
+//
+// Validations that are not configured fail the check.
+func (p *Preflight) SetConfig(config preflight.CheckConfig) error {
+	p.validator.WarnValidations = sets.New[string]()
+	p.existingResourcesSampleSize = ExistingResourcesSampleSizeDefault
+
+	if sampleSizeConfig, found := config[existingResourcesSampleSizeConfigKey]; found {
+		sampleSize, ok := sampleSizeConfig.(float64)
+		if !ok || sampleSize < 0 || sampleSize != float64(int64(sampleSize)) {
+			return fmt.Errorf("expected %q config to be a non-negative integer (given: '%v')",
+				existingResourcesSampleSizeConfigKey, sampleSizeConfig)
+		}
+		p.existingResourcesSampleSize = int64(sampleSize)
+	}
+
+	validationsConfig, found := config[validationsConfigKey]
+	if !found {
+		return nil
+	}
+
+	actionsByName, ok := validationsConfig.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected %q config to be a map of validation names to actions", validationsConfigKey)
+	}
+
+	knownNames := sets.New[string](NoInvalidExistingResources)
+	for _, validation := range p.validator.Validations {
+		knownNames.Insert(validation.Name())
+	}
+
+	for name, action := range actionsByName {
+		if !knownNames.Has(name) {
+			return fmt.Errorf("unknown validation %q (known: %s)", name, strings.Join(sets.List(knownNames), ", "))
+		}
+		switch action {
+		case ValidationActionError:
+		case ValidationActionWarn:
+			p.validator.WarnValidations.Insert(name)
+		default:
+			return fmt.Errorf("expected action for validation %q to be one of: %s, %s (given: '%v')",
+				name, ValidationActionError, ValidationActionWarn, action)
+		}
+	}
+
 	return nil
 }
 
@@ -60,6 +136,20 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 	}
 	crdCli := dCli.Resource(v1.SchemeGroupVersion.WithResource("customresourcedefinitions"))
 
+	validator := p.validator
+
+	if p.existingResourcesSampleSize > 0 {
+		existingResourcesValidator := ExistingResourcesValidator{DynamicClient: dCli, SampleSize: p.existingResourcesSampleSize}
+
+		validator = &Validator{
+			Validations: append(append([]Validation{}, p.validator.Validations...),
+				NewValidationFunc(NoInvalidExistingResources, func(old, new v1.CustomResourceDefinition) error {
+					return existingResourcesValidator.Validate(ctx, old, new)
+				})),
+			WarnValidations: p.validator.WarnValidations,
+		}
+	}
+
 	validateErrs := []error{}
 	for _, change := range changeGraph.All() {
 		// Loop through all the changes looking for "upsert" operations on
@@ -99,9 +189,13 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 			return fmt.Errorf("couldn't convert new CRD resource to a CRD object: %w", err)
 		}
 
-		if err = p.validator.Validate(*oldCRD, *newCRD); err != nil {
+		warnings, err := validator.ValidateWithWarnings(*oldCRD, *newCRD)
+		if err != nil {
 			validateErrs = append(validateErrs, err)
 		}
+		for _, warning := range warnings {
+			p.ui.PrintLinef("Warning: %s", warning)
+		}
 	}
 
 	if len(validateErrs) > 0 {
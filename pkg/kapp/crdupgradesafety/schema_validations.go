@@ -0,0 +1,302 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FieldValidateFunc validates changes to a single field of
+// the OpenAPI schema. Path is formatted similarly to JSON path
+// (e.g. ".spec.containers[*].name").
+type FieldValidateFunc func(path string, old, new *v1.JSONSchemaProps) error
+
+// NewFieldValidationFunc returns a validation that runs provided function
+// against each field that exists in both old and new schema
+// for each version served by both old and new CRDs
+func NewFieldValidationFunc(name string, fieldFunc FieldValidateFunc) Validation {
+	return NewValidationFunc(name, func(old, new v1.CustomResourceDefinition) error {
+		return validateServedVersionSchemas(old, new, func(oldFields, newFields map[string]*v1.JSONSchemaProps) []error {
+			var errs []error
+			for _, path := range sortedFieldPaths(oldFields) {
+				newField, found := newFields[path]
+				if !found {
+					continue
+				}
+				if err := fieldFunc(path, oldFields[path], newField); err != nil {
+					errs = append(errs, fmt.Errorf("field %q: %w", path, err))
+				}
+			}
+			return errs
+		})
+	})
+}
+
+// NoExistingFieldRemoved fails if a field present in the old
+// schema is not present in the new schema of the same version
+func NoExistingFieldRemoved(old, new v1.CustomResourceDefinition) error {
+	return validateServedVersionSchemas(old, new, func(oldFields, newFields map[string]*v1.JSONSchemaProps) []error {
+		var errs []error
+		var removedPaths []string
+
+		for _, path := range sortedFieldPaths(oldFields) {
+			if _, found := newFields[path]; found {
+				continue
+			}
+			// Only report top most removed field (parents are sorted before children)
+			if hasParentFieldPath(removedPaths, path) {
+				continue
+			}
+			removedPaths = append(removedPaths, path)
+			errs = append(errs, fmt.Errorf("field %q: removed", path))
+		}
+		return errs
+	})
+}
+
+// NoRequiredFieldAdded fails if a field became required
+func NoRequiredFieldAdded(_ string, old, new *v1.JSONSchemaProps) error {
+	added := sets.New[string](new.Required...).Difference(sets.New[string](old.Required...))
+	if added.Len() > 0 {
+		return fmt.Errorf("new required fields added: %s", strings.Join(sets.List(added), ", "))
+	}
+	return nil
+}
+
+// NoEnumNarrowed fails if allowed enum values were removed
+// or enum constraint was added to a field that did not have it
+func NoEnumNarrowed(_ string, old, new *v1.JSONSchemaProps) error {
+	if len(new.Enum) == 0 {
+		return nil
+	}
+	if len(old.Enum) == 0 {
+		return fmt.Errorf("enum constraint added")
+	}
+
+	var removed []string
+
+	for _, oldVal := range old.Enum {
+		var found bool
+		for _, newVal := range new.Enum {
+			if bytes.Equal(oldVal.Raw, newVal.Raw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, string(oldVal.Raw))
+		}
+	}
+
+	if len(removed) > 0 {
+		return fmt.Errorf("enum values removed: %s", strings.Join(removed, ", "))
+	}
+	return nil
+}
+
+// NoConstraintTightened fails if numeric, length, size
+// or pattern constraints became stricter
+func NoConstraintTightened(_ string, old, new *v1.JSONSchemaProps) error {
+	var errs []error
+
+	if err := noMaximumDecreased("maximum", old.Maximum, new.Maximum); err != nil {
+		errs = append(errs, err)
+	}
+	if err := noMinimumIncreased("minimum", old.Minimum, new.Minimum); err != nil {
+		errs = append(errs, err)
+	}
+	if !old.ExclusiveMaximum && new.ExclusiveMaximum {
+		errs = append(errs, fmt.Errorf("exclusiveMaximum enabled"))
+	}
+	if !old.ExclusiveMinimum && new.ExclusiveMinimum {
+		errs = append(errs, fmt.Errorf("exclusiveMinimum enabled"))
+	}
+
+	intConstraints := []struct {
+		name     string
+		old, new *int64
+		isMax    bool
+	}{
+		{"maxLength", old.MaxLength, new.MaxLength, true},
+		{"minLength", old.MinLength, new.MinLength, false},
+		{"maxItems", old.MaxItems, new.MaxItems, true},
+		{"minItems", old.MinItems, new.MinItems, false},
+		{"maxProperties", old.MaxProperties, new.MaxProperties, true},
+		{"minProperties", old.MinProperties, new.MinProperties, false},
+	}
+
+	for _, c := range intConstraints {
+		var err error
+		if c.isMax {
+			err = noMaximumDecreased(c.name, int64PtrToFloat64Ptr(c.old), int64PtrToFloat64Ptr(c.new))
+		} else {
+			err = noMinimumIncreased(c.name, int64PtrToFloat64Ptr(c.old), int64PtrToFloat64Ptr(c.new))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if new.Pattern != old.Pattern && len(new.Pattern) > 0 {
+		if len(old.Pattern) == 0 {
+			errs = append(errs, fmt.Errorf("pattern %q added", new.Pattern))
+		} else {
+			errs = append(errs, fmt.Errorf("pattern changed from %q to %q", old.Pattern, new.Pattern))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NoTypeChange fails if type of a field changed
+func NoTypeChange(_ string, old, new *v1.JSONSchemaProps) error {
+	if old.Type != new.Type {
+		return fmt.Errorf("type changed from %q to %q", old.Type, new.Type)
+	}
+	return nil
+}
+
+// NoDefaultValueChange fails if default value of a field was added, removed or changed
+func NoDefaultValueChange(_ string, old, new *v1.JSONSchemaProps) error {
+	switch {
+	case old.Default == nil && new.Default == nil:
+		return nil
+	case old.Default == nil:
+		return fmt.Errorf("default value %s added", new.Default.Raw)
+	case new.Default == nil:
+		return fmt.Errorf("default value %s removed", old.Default.Raw)
+	case !bytes.Equal(old.Default.Raw, new.Default.Raw):
+		return fmt.Errorf("default value changed from %s to %s", old.Default.Raw, new.Default.Raw)
+	default:
+		return nil
+	}
+}
+
+func noMaximumDecreased(name string, old, new *float64) error {
+	switch {
+	case new == nil:
+		return nil
+	case old == nil:
+		return fmt.Errorf("%s constraint %v added", name, *new)
+	case *new < *old:
+		return fmt.Errorf("%s decreased from %v to %v", name, *old, *new)
+	default:
+		return nil
+	}
+}
+
+func noMinimumIncreased(name string, old, new *float64) error {
+	switch {
+	case new == nil:
+		return nil
+	case old == nil:
+		return fmt.Errorf("%s constraint %v added", name, *new)
+	case *new > *old:
+		return fmt.Errorf("%s increased from %v to %v", name, *old, *new)
+	default:
+		return nil
+	}
+}
+
+func int64PtrToFloat64Ptr(val *int64) *float64 {
+	if val == nil {
+		return nil
+	}
+	result := float64(*val)
+	return &result
+}
+
+type schemaFieldsValidateFunc func(oldFields, newFields map[string]*v1.JSONSchemaProps) []error
+
+// validateServedVersionSchemas compares schemas of versions
+// that are served by both old and new CRDs. Version removal
+// is covered by NoStoredVersionRemoved validation.
+func validateServedVersionSchemas(old, new v1.CustomResourceDefinition, validateFunc schemaFieldsValidateFunc) error {
+	oldVersions := map[string]v1.CustomResourceDefinitionVersion{}
+	for _, version := range old.Spec.Versions {
+		oldVersions[version.Name] = version
+	}
+
+	var errs []error
+
+	for _, newVersion := range new.Spec.Versions {
+		oldVersion, found := oldVersions[newVersion.Name]
+		if !found || !oldVersion.Served || !newVersion.Served {
+			continue
+		}
+
+		oldFields := flattenVersionSchema(oldVersion)
+		newFields := flattenVersionSchema(newVersion)
+
+		for _, err := range validateFunc(oldFields, newFields) {
+			errs = append(errs, fmt.Errorf("version %q: %w", newVersion.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func flattenVersionSchema(version v1.CustomResourceDefinitionVersion) map[string]*v1.JSONSchemaProps {
+	fields := map[string]*v1.JSONSchemaProps{}
+	if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+		flattenSchema("", version.Schema.OpenAPIV3Schema, fields)
+	}
+	return fields
+}
+
+func flattenSchema(path string, schema *v1.JSONSchemaProps, fields map[string]*v1.JSONSchemaProps) {
+	if len(path) == 0 {
+		fields["."] = schema
+	} else {
+		fields[path] = schema
+	}
+
+	for name, prop := range schema.Properties {
+		prop := prop
+		flattenSchema(path+"."+name, &prop, fields)
+	}
+	if schema.Items != nil && schema.Items.Schema != nil {
+		flattenSchema(path+"[*]", schema.Items.Schema, fields)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		flattenSchema(path+"{*}", schema.AdditionalProperties.Schema, fields)
+	}
+}
+
+func hasParentFieldPath(parents []string, path string) bool {
+	for _, parent := range parents {
+		if isChildFieldPath(parent, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func isChildFieldPath(parent, path string) bool {
+	if !strings.HasPrefix(path, parent) || len(path) == len(parent) {
+		return false
+	}
+	switch path[len(parent)] {
+	case '.', '[', '{':
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedFieldPaths(fields map[string]*v1.JSONSchemaProps) []string {
+	var paths []string
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
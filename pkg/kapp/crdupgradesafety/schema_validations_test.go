@@ -0,0 +1,251 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestNoExistingFieldRemoved(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		old         v1.CustomResourceDefinition
+		new         v1.CustomResourceDefinition
+		expectedErr string
+	}{
+		{
+			name: "no fields removed, no error",
+			old: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": objectSchema("foo")},
+			}),
+			new: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": objectSchema("foo", "bar")},
+			}),
+		},
+		{
+			name: "field removed, error with top most path",
+			old: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": {
+					Properties: map[string]v1.JSONSchemaProps{"foo": objectSchema("bar", "baz")},
+				}},
+			}),
+			new: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": objectSchema()},
+			}),
+			expectedErr: `version "v1": field ".spec.foo": removed`,
+		},
+		{
+			name: "field removed from array items, error",
+			old: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"items": {
+					Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{
+						Properties: map[string]v1.JSONSchemaProps{"name": {Type: "string"}},
+					}},
+				}},
+			}),
+			new: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"items": {
+					Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{}},
+				}},
+			}),
+			expectedErr: `field ".items[*].name": removed`,
+		},
+		{
+			name: "field removed from version that is no longer served, no error",
+			old: crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": objectSchema("foo")},
+			}),
+			new: crdWithSchema("v1", false, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": objectSchema()},
+			}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NoExistingFieldRemoved(tc.old, tc.new)
+			if len(tc.expectedErr) > 0 {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFieldValidations(t *testing.T) {
+	int64Ptr := func(val int64) *int64 { return &val }
+	float64Ptr := func(val float64) *float64 { return &val }
+
+	for _, tc := range []struct {
+		name        string
+		fieldFunc   FieldValidateFunc
+		old         v1.JSONSchemaProps
+		new         v1.JSONSchemaProps
+		expectedErr string
+	}{
+		{
+			name:      "required field added, error",
+			fieldFunc: NoRequiredFieldAdded,
+			old:       v1.JSONSchemaProps{Required: []string{"foo"}},
+			new:       v1.JSONSchemaProps{Required: []string{"foo", "bar"}},
+
+			expectedErr: "new required fields added: bar",
+		},
+		{
+			name:      "required field removed, no error",
+			fieldFunc: NoRequiredFieldAdded,
+			old:       v1.JSONSchemaProps{Required: []string{"foo"}},
+			new:       v1.JSONSchemaProps{},
+		},
+		{
+			name:      "enum widened, no error",
+			fieldFunc: NoEnumNarrowed,
+			old:       v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte(`"a"`)}}},
+			new:       v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}}},
+		},
+		{
+			name:        "enum narrowed, error",
+			fieldFunc:   NoEnumNarrowed,
+			old:         v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}}},
+			new:         v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte(`"a"`)}}},
+			expectedErr: `enum values removed: "b"`,
+		},
+		{
+			name:        "enum added, error",
+			fieldFunc:   NoEnumNarrowed,
+			old:         v1.JSONSchemaProps{},
+			new:         v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte(`"a"`)}}},
+			expectedErr: "enum constraint added",
+		},
+		{
+			name:        "maximum decreased, error",
+			fieldFunc:   NoConstraintTightened,
+			old:         v1.JSONSchemaProps{Maximum: float64Ptr(10)},
+			new:         v1.JSONSchemaProps{Maximum: float64Ptr(5)},
+			expectedErr: "maximum decreased from 10 to 5",
+		},
+		{
+			name:      "maximum increased, no error",
+			fieldFunc: NoConstraintTightened,
+			old:       v1.JSONSchemaProps{Maximum: float64Ptr(5)},
+			new:       v1.JSONSchemaProps{Maximum: float64Ptr(10)},
+		},
+		{
+			name:        "minLength added, error",
+			fieldFunc:   NoConstraintTightened,
+			old:         v1.JSONSchemaProps{},
+			new:         v1.JSONSchemaProps{MinLength: int64Ptr(3)},
+			expectedErr: "minLength constraint 3 added",
+		},
+		{
+			name:        "pattern changed, error",
+			fieldFunc:   NoConstraintTightened,
+			old:         v1.JSONSchemaProps{Pattern: "^a"},
+			new:         v1.JSONSchemaProps{Pattern: "^b"},
+			expectedErr: `pattern changed from "^a" to "^b"`,
+		},
+		{
+			name:      "pattern removed, no error",
+			fieldFunc: NoConstraintTightened,
+			old:       v1.JSONSchemaProps{Pattern: "^a"},
+			new:       v1.JSONSchemaProps{},
+		},
+		{
+			name:        "type changed, error",
+			fieldFunc:   NoTypeChange,
+			old:         v1.JSONSchemaProps{Type: "string"},
+			new:         v1.JSONSchemaProps{Type: "integer"},
+			expectedErr: `type changed from "string" to "integer"`,
+		},
+		{
+			name:        "default changed, error",
+			fieldFunc:   NoDefaultValueChange,
+			old:         v1.JSONSchemaProps{Default: &v1.JSON{Raw: []byte(`1`)}},
+			new:         v1.JSONSchemaProps{Default: &v1.JSON{Raw: []byte(`2`)}},
+			expectedErr: "default value changed from 1 to 2",
+		},
+		{
+			name:      "default unchanged, no error",
+			fieldFunc: NoDefaultValueChange,
+			old:       v1.JSONSchemaProps{Default: &v1.JSON{Raw: []byte(`1`)}},
+			new:       v1.JSONSchemaProps{Default: &v1.JSON{Raw: []byte(`1`)}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			old := crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": tc.old},
+			})
+			new := crdWithSchema("v1", true, v1.JSONSchemaProps{
+				Properties: map[string]v1.JSONSchemaProps{"spec": tc.new},
+			})
+
+			err := NewFieldValidationFunc("test", tc.fieldFunc).Validate(old, new)
+			if len(tc.expectedErr) > 0 {
+				require.ErrorContains(t, err, `field ".spec": `+tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatorWarnings(t *testing.T) {
+	old := crdWithSchema("v1", true, v1.JSONSchemaProps{
+		Properties: map[string]v1.JSONSchemaProps{"spec": {Type: "string"}},
+	})
+	new := crdWithSchema("v1", true, v1.JSONSchemaProps{
+		Properties: map[string]v1.JSONSchemaProps{"spec": {Type: "integer"}},
+	})
+
+	v := Validator{
+		Validations:     []Validation{NewFieldValidationFunc("NoTypeChange", NoTypeChange)},
+		WarnValidations: sets.New[string]("NoTypeChange"),
+	}
+
+	warnings, err := v.ValidateWithWarnings(old, new)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorContains(t, warnings[0], `type changed from "string" to "integer"`)
+}
+
+func TestPreflightSetConfig(t *testing.T) {
+	p := NewPreflight(nil, nil, true)
+
+	err := p.SetConfig(map[string]any{"validations": map[string]any{"NoDefaultValueChange": "warn"}})
+	require.NoError(t, err)
+	require.True(t, p.validator.WarnValidations.Has("NoDefaultValueChange"))
+
+	err = p.SetConfig(nil)
+	require.NoError(t, err)
+	require.False(t, p.validator.WarnValidations.Has("NoDefaultValueChange"))
+
+	err = p.SetConfig(map[string]any{"validations": map[string]any{"Unknown": "warn"}})
+	require.ErrorContains(t, err, `unknown validation "Unknown"`)
+
+	err = p.SetConfig(map[string]any{"validations": map[string]any{"NoTypeChange": "ignore"}})
+	require.ErrorContains(t, err, "expected action for validation")
+}
+
+func crdWithSchema(version string, served bool, schema v1.JSONSchemaProps) v1.CustomResourceDefinition {
+	return v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{{
+				Name:   version,
+				Served: served,
+				Schema: &v1.CustomResourceValidation{OpenAPIV3Schema: &schema},
+			}},
+		},
+	}
+}
+
+func objectSchema(propNames ...string) v1.JSONSchemaProps {
+	props := map[string]v1.JSONSchemaProps{}
+	for _, name := range propNames {
+		props[name] = v1.JSONSchemaProps{Type: "string"}
+	}
+	return v1.JSONSchemaProps{Type: "object", Properties: props}
+}
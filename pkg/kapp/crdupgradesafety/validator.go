@@ -50,12 +50,29 @@ func (vf *ValidationFunc) Validate(old, new v1.CustomResourceDefinition) error {
 
 type Validator struct {
 	Validations []Validation
+	// WarnValidations contains names of validations
+	// that should produce warnings instead of failing
+	WarnValidations sets.Set[string]
 }
 
 func (v *Validator) Validate(old, new v1.CustomResourceDefinition) error {
+	_, err := v.ValidateWithWarnings(old, new)
+	return err
+}
+
+// ValidateWithWarnings returns failures from validations
+// configured as warnings separately from the returned error
+func (v *Validator) ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error) {
 	validateErrs := []error{}
+	warnings := []error{}
 	for _, validation := range v.Validations {
 		if err := validation.Validate(old, new); err != nil {
+			if v.WarnValidations.Has(validation.Name()) {
+				warnings = append(warnings, fmt.Errorf("CustomResourceDefinition %s upgrade safety validation %q reported: %w",
+					new.Name, validation.Name(), err))
+				continue
+			}
+
 			formattedErr := fmt.Errorf("CustomResourceDefinition %s failed upgrade safety validation. %q validation failed: %w",
 				new.Name, validation.Name(), err)
 
@@ -63,9 +80,9 @@ func (v *Validator) Validate(old, new v1.CustomResourceDefinition) error {
 		}
 	}
 	if len(validateErrs) > 0 {
-		return errors.Join(validateErrs...)
+		return warnings, errors.Join(validateErrs...)
 	}
-	return nil
+	return warnings, nil
 }
 
 func NoScopeChange(old, new v1.CustomResourceDefinition) error {
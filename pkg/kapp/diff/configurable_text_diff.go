@@ -33,24 +33,52 @@ func (d ConfigurableTextDiff) Full() TextDiff {
 }
 
 func (d ConfigurableTextDiff) Masked(rules []ctlconf.DiffMaskRule) (TextDiff, error) {
+	existingRes, newRes, err := d.maskedResources(rules)
+	if err != nil {
+		return TextDiff{}, err
+	}
+	return d.calculate(existingRes, newRes), nil
+}
+
+// Resources returns compared resources (optionally masked).
+// Ignored changes are represented by same existing and new resource.
+func (d ConfigurableTextDiff) Resources(rules []ctlconf.DiffMaskRule, mask bool) (ctlres.Resource, ctlres.Resource, error) {
+	existingRes, newRes := d.existingRes, d.newRes
+
+	if mask {
+		var err error
+		existingRes, newRes, err = d.maskedResources(rules)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if newRes == nil && d.ignored {
+		newRes = existingRes
+	}
+
+	return existingRes, newRes, nil
+}
+
+func (d ConfigurableTextDiff) maskedResources(rules []ctlconf.DiffMaskRule) (ctlres.Resource, ctlres.Resource, error) {
 	var existingRes, newRes ctlres.Resource
 	var err error
 
 	if d.existingRes != nil {
 		existingRes, err = NewMaskedResource(d.existingRes, rules).Resource()
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking existing resource: %w", err)
+			return nil, nil, fmt.Errorf("Masking existing resource: %w", err)
 		}
 	}
 
 	if d.newRes != nil {
 		newRes, err = NewMaskedResource(d.newRes, rules).Resource()
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking new resource: %w", err)
+			return nil, nil, fmt.Errorf("Masking new resource: %w", err)
 		}
 	}
 
-	return d.calculate(existingRes, newRes), nil
+	return existingRes, newRes, nil
 }
 
 func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) TextDiff {
@@ -0,0 +1,181 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type FieldChangeOp string
+
+const (
+	FieldChangeOpAdd     FieldChangeOp = "add"
+	FieldChangeOpRemove  FieldChangeOp = "remove"
+	FieldChangeOpReplace FieldChangeOp = "replace"
+)
+
+// FieldChange describes change to a single field identified by JSON pointer (RFC 6901)
+type FieldChange struct {
+	Op       FieldChangeOp `json:"op"`
+	Path     string        `json:"path"`
+	OldValue interface{}   `json:"oldValue,omitempty"`
+	NewValue interface{}   `json:"newValue,omitempty"`
+}
+
+// MarshalJSON includes values relevant to the op even if they are
+// empty (e.g. false or ""), since omitting them would change meaning
+func (c FieldChange) MarshalJSON() ([]byte, error) {
+	doc := struct {
+		Op       FieldChangeOp `json:"op"`
+		Path     string        `json:"path"`
+		OldValue *interface{}  `json:"oldValue,omitempty"`
+		NewValue *interface{}  `json:"newValue,omitempty"`
+	}{Op: c.Op, Path: c.Path}
+
+	if c.Op != FieldChangeOpAdd {
+		doc.OldValue = &c.OldValue
+	}
+	if c.Op != FieldChangeOpRemove {
+		doc.NewValue = &c.NewValue
+	}
+	return json.Marshal(doc)
+}
+
+// JSONPatchOp is a single JSON Patch (RFC 6902) operation
+type JSONPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON always includes value for non-remove ops as required by RFC 6902
+func (o JSONPatchOp) MarshalJSON() ([]byte, error) {
+	doc := struct {
+		Op    string       `json:"op"`
+		Path  string       `json:"path"`
+		Value *interface{} `json:"value,omitempty"`
+	}{Op: o.Op, Path: o.Path}
+
+	if o.Op != string(FieldChangeOpRemove) {
+		doc.Value = &o.Value
+	}
+	return json.Marshal(doc)
+}
+
+type FieldChanges []FieldChange
+
+// NewFieldChanges returns changes that turn existing resource into new resource.
+// Changes are ordered so that they can be applied sequentially
+// (e.g. array items are removed starting from the last one).
+func NewFieldChanges(existingRes, newRes ctlres.Resource) FieldChanges {
+	var existingObj, newObj interface{}
+	if existingRes != nil {
+		existingObj = existingRes.DeepCopyRaw()
+	}
+	if newRes != nil {
+		newObj = newRes.DeepCopyRaw()
+	}
+
+	switch {
+	case existingObj == nil && newObj == nil:
+		return nil
+	case existingObj == nil:
+		return FieldChanges{{Op: FieldChangeOpAdd, Path: "", NewValue: newObj}}
+	case newObj == nil:
+		return FieldChanges{{Op: FieldChangeOpRemove, Path: "", OldValue: existingObj}}
+	default:
+		return fieldChangesCalculator{}.calculate(existingObj, newObj, nil)
+	}
+}
+
+// JSONPatch returns JSON Patch operations equivalent to field changes
+func (cs FieldChanges) JSONPatch() []JSONPatchOp {
+	result := []JSONPatchOp{}
+	for _, c := range cs {
+		switch c.Op {
+		case FieldChangeOpRemove:
+			result = append(result, JSONPatchOp{Op: string(c.Op), Path: c.Path})
+		default:
+			result = append(result, JSONPatchOp{Op: string(c.Op), Path: c.Path, Value: c.NewValue})
+		}
+	}
+	return result
+}
+
+type fieldChangesCalculator struct{}
+
+func (c fieldChangesCalculator) calculate(left, right interface{}, path []string) FieldChanges {
+	switch typedLeft := left.(type) {
+	case map[string]interface{}:
+		if typedRight, ok := right.(map[string]interface{}); ok {
+			var keys []string
+			for k := range typedLeft {
+				keys = append(keys, k)
+			}
+			for k := range typedRight {
+				if _, found := typedLeft[k]; !found {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			var result FieldChanges
+
+			for _, k := range keys {
+				keyPath := append(append([]string{}, path...), k)
+				leftVal, leftFound := typedLeft[k]
+				rightVal, rightFound := typedRight[k]
+
+				switch {
+				case leftFound && rightFound:
+					result = append(result, c.calculate(leftVal, rightVal, keyPath)...)
+				case leftFound:
+					result = append(result, FieldChange{Op: FieldChangeOpRemove, Path: c.pointer(keyPath), OldValue: leftVal})
+				default:
+					result = append(result, FieldChange{Op: FieldChangeOpAdd, Path: c.pointer(keyPath), NewValue: rightVal})
+				}
+			}
+			return result
+		}
+
+	case []interface{}:
+		if typedRight, ok := right.([]interface{}); ok {
+			var result FieldChanges
+
+			for i := 0; i < len(typedLeft) && i < len(typedRight); i++ {
+				idxPath := append(append([]string{}, path...), strconv.Itoa(i))
+				result = append(result, c.calculate(typedLeft[i], typedRight[i], idxPath)...)
+			}
+			// Remove from the end so that indexes of remaining items do not shift
+			for i := len(typedLeft) - 1; i >= len(typedRight); i-- {
+				idxPath := append(append([]string{}, path...), strconv.Itoa(i))
+				result = append(result, FieldChange{Op: FieldChangeOpRemove, Path: c.pointer(idxPath), OldValue: typedLeft[i]})
+			}
+			for i := len(typedLeft); i < len(typedRight); i++ {
+				idxPath := append(append([]string{}, path...), strconv.Itoa(i))
+				result = append(result, FieldChange{Op: FieldChangeOpAdd, Path: c.pointer(idxPath), NewValue: typedRight[i]})
+			}
+			return result
+		}
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return nil
+	}
+	return FieldChanges{{Op: FieldChangeOpReplace, Path: c.pointer(path), OldValue: left, NewValue: right}}
+}
+
+func (fieldChangesCalculator) pointer(path []string) string {
+	var result string
+	for _, piece := range path {
+		result += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(piece)
+	}
+	return result
+}
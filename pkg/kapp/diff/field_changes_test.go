@@ -0,0 +1,106 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestNewFieldChanges(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+  labels:
+    removed: "1"
+    a/b: "1"
+data:
+  list:
+  - a
+  - b
+  - c
+  changed: old
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+  labels:
+    a/b: "2"
+data:
+  list:
+  - a
+  changed: new
+  added:
+    key: val
+`))
+
+	changes := ctldiff.NewFieldChanges(existingRes, newRes)
+
+	expectedChanges := ctldiff.FieldChanges{
+		{Op: ctldiff.FieldChangeOpAdd, Path: "/data/added", NewValue: map[string]interface{}{"key": "val"}},
+		{Op: ctldiff.FieldChangeOpReplace, Path: "/data/changed", OldValue: "old", NewValue: "new"},
+		{Op: ctldiff.FieldChangeOpRemove, Path: "/data/list/2", OldValue: "c"},
+		{Op: ctldiff.FieldChangeOpRemove, Path: "/data/list/1", OldValue: "b"},
+		{Op: ctldiff.FieldChangeOpReplace, Path: "/metadata/labels/a~1b", OldValue: "1", NewValue: "2"},
+		{Op: ctldiff.FieldChangeOpRemove, Path: "/metadata/labels/removed", OldValue: "1"},
+	}
+	require.Equal(t, expectedChanges, changes)
+
+	expectedPatch := []ctldiff.JSONPatchOp{
+		{Op: "add", Path: "/data/added", Value: map[string]interface{}{"key": "val"}},
+		{Op: "replace", Path: "/data/changed", Value: "new"},
+		{Op: "remove", Path: "/data/list/2"},
+		{Op: "remove", Path: "/data/list/1"},
+		{Op: "replace", Path: "/metadata/labels/a~1b", Value: "2"},
+		{Op: "remove", Path: "/metadata/labels/removed"},
+	}
+	require.Equal(t, expectedPatch, changes.JSONPatch())
+}
+
+func TestNewFieldChangesWithoutExisting(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+`))
+
+	changes := ctldiff.NewFieldChanges(nil, newRes)
+
+	expectedChanges := ctldiff.FieldChanges{{
+		Op:       ctldiff.FieldChangeOpAdd,
+		Path:     "",
+		NewValue: map[string]interface{}{"metadata": map[string]interface{}{"name": "my-res"}},
+	}}
+	require.Equal(t, expectedChanges, changes)
+	require.Len(t, ctldiff.NewFieldChanges(newRes, newRes), 0)
+}
+
+func TestFieldChangesJSONWithEmptyValues(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+data:
+  enabled: true
+  removed: ""
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+data:
+  enabled: false
+`))
+
+	changes := ctldiff.NewFieldChanges(existingRes, newRes)
+
+	changesBs, err := json.Marshal(changes)
+	require.NoError(t, err)
+	require.Equal(t, `[{"op":"replace","path":"/data/enabled","oldValue":true,"newValue":false},`+
+		`{"op":"remove","path":"/data/removed","oldValue":""}]`, string(changesBs))
+
+	patchBs, err := json.Marshal(changes.JSONPatch())
+	require.NoError(t, err)
+	require.Equal(t, `[{"op":"replace","path":"/data/enabled","value":false},`+
+		`{"op":"remove","path":"/data/removed"}]`, string(patchBs))
+}
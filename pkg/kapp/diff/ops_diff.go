@@ -5,6 +5,7 @@ package diff
 
 import (
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
 
 	"github.com/cppforlife/go-patch/patch"
@@ -19,7 +20,37 @@ func (l OpsDiff) MinimalMD5() string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(l.MinimalString())))
 }
 
-func (l OpsDiff) FullString() string { return "" }
+// FullString returns all operations (including values)
+// as indented JSON (e.g. for consumption by other tools)
+func (l OpsDiff) FullString() (string, error) {
+	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
+	if err != nil {
+		return "", fmt.Errorf("Building op definitions: %w", err)
+	}
+
+	bs, err := json.MarshalIndent(opsDefs, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Serializing op definitions: %w", err)
+	}
+
+	return string(bs), nil
+}
+
+// Paths returns paths (e.g. /spec/replicas) of changed fields
+func (l OpsDiff) Paths() ([]string, error) {
+	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
+	if err != nil {
+		return nil, fmt.Errorf("Building op definitions: %w", err)
+	}
+
+	var result []string
+	for _, opDef := range opsDefs {
+		if opDef.Path != nil {
+			result = append(result, *opDef.Path)
+		}
+	}
+	return result, nil
+}
 
 func (l OpsDiff) MinimalString() string {
 	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
@@ -0,0 +1,104 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestOpsDiff(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+spec:
+  replicas: 1
+  removed: true
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+  labels:
+    app: web
+spec:
+  replicas: 3
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	change, err := changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+
+	opsDiff := change.OpsDiff()
+	require.True(t, opsDiff.HasChanges())
+
+	fullStr, err := opsDiff.FullString()
+	require.NoError(t, err)
+
+	// Unlike minimal string, full string includes values
+	require.Equal(t, `[
+  {
+    "Type": "test",
+    "Path": "/metadata/labels",
+    "Absent": true
+  },
+  {
+    "Type": "replace",
+    "Path": "/metadata/labels?",
+    "Value": {
+      "app": "web"
+    }
+  },
+  {
+    "Type": "test",
+    "Path": "/spec/removed",
+    "Value": true
+  },
+  {
+    "Type": "remove",
+    "Path": "/spec/removed"
+  },
+  {
+    "Type": "test",
+    "Path": "/spec/replicas",
+    "Value": 1
+  },
+  {
+    "Type": "replace",
+    "Path": "/spec/replicas",
+    "Value": 3
+  }
+]`, fullStr)
+
+	paths, err := opsDiff.Paths()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/metadata/labels", "/metadata/labels?",
+		"/spec/removed", "/spec/removed",
+		"/spec/replicas", "/spec/replicas",
+	}, paths)
+}
+
+func TestOpsDiffWithoutChanges(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+metadata:
+  name: my-res
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	change, err := changeFactory.NewExactChange(res, res.DeepCopy())
+	require.NoError(t, err)
+
+	opsDiff := change.OpsDiff()
+	require.False(t, opsDiff.HasChanges())
+
+	fullStr, err := opsDiff.FullString()
+	require.NoError(t, err)
+	require.Equal(t, "[]", fullStr)
+}
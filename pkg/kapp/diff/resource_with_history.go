@@ -63,6 +63,26 @@ func (r ResourceWithHistory) LastAppliedResource() ctlres.Resource {
 	return nil
 }
 
+// RecordedLastAppliedResource returns "last applied" resource that was saved
+// regardless of whether resource on the cluster changed since then.
+// Nil is returned if resource does not have a saved copy.
+func (r ResourceWithHistory) RecordedLastAppliedResource() (ctlres.Resource, error) {
+	lastAppliedResBytes := r.resource.Annotations()[appliedResAnnKey]
+	if len(lastAppliedResBytes) == 0 {
+		return nil, nil
+	}
+
+	lastAppliedRes, err := ctlres.NewResourceFromBytes([]byte(lastAppliedResBytes))
+	if err != nil {
+		return nil, fmt.Errorf("Parsing last applied resource: %w", err)
+	}
+	if lastAppliedRes == nil {
+		return nil, nil
+	}
+
+	return lastAppliedRes, nil
+}
+
 func (r ResourceWithHistory) AllowsRecordingLastApplied() bool {
 	_, found := r.resource.Annotations()[disableOriginalAnnKey]
 	return !found
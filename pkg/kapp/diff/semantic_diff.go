@@ -0,0 +1,494 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cppforlife/color"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+type SemanticChangeOp string
+
+const (
+	SemanticChangeOpAdd     SemanticChangeOp = "add"
+	SemanticChangeOpRemove  SemanticChangeOp = "remove"
+	SemanticChangeOpReplace SemanticChangeOp = "replace"
+	SemanticChangeOpMove    SemanticChangeOp = "move"
+)
+
+// SemanticChange describes change to a single field or list item.
+// List items matched by merge keys are identified as [key=value]
+// (e.g. spec.template.spec.containers[name=app].image).
+type SemanticChange struct {
+	Op       SemanticChangeOp
+	Path     string
+	OldValue interface{}
+	NewValue interface{}
+
+	// Only set for moved list items
+	OldIndex int
+	NewIndex int
+}
+
+type SemanticChanges []SemanticChange
+
+// SemanticDiff compares resources as trees instead of lines of text.
+// List items are matched by merge keys (configured via diff list merge key rules
+// or known from built-in Kubernetes types) or by their content, hence
+// reordering of list items is reported as moves instead of value changes.
+type SemanticDiff struct {
+	existingRes, newRes ctlres.Resource
+	mergeKeyRules       []ctlconf.DiffListMergeKeyRule
+}
+
+func NewSemanticDiff(existingRes, newRes ctlres.Resource,
+	mergeKeyRules []ctlconf.DiffListMergeKeyRule) SemanticDiff {
+
+	return SemanticDiff{existingRes, newRes, mergeKeyRules}
+}
+
+func (d SemanticDiff) Changes() SemanticChanges {
+	var existingObj, newObj interface{}
+	if d.existingRes != nil {
+		existingObj = d.existingRes.DeepCopyRaw()
+	}
+	if d.newRes != nil {
+		newObj = d.newRes.DeepCopyRaw()
+	}
+
+	switch {
+	case existingObj == nil && newObj == nil:
+		return nil
+	case existingObj == nil:
+		return SemanticChanges{{Op: SemanticChangeOpAdd, NewValue: newObj}}
+	case newObj == nil:
+		return SemanticChanges{{Op: SemanticChangeOpRemove, OldValue: existingObj}}
+	}
+
+	calc := semanticDiffCalculator{rules: d.applicableRules(d.newRes)}
+	return calc.calculate(existingObj, newObj, semanticPath{}, d.schema(d.newRes))
+}
+
+func (d SemanticDiff) applicableRules(res ctlres.Resource) []ctlconf.DiffListMergeKeyRule {
+	var result []ctlconf.DiffListMergeKeyRule
+	for _, rule := range d.mergeKeyRules {
+		matcher := ctlres.AnyMatcher{
+			Matchers: ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers(),
+		}
+		if matcher.Matches(res) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// schema returns patch metadata for built-in types which includes
+// merge keys used by strategic merge patch (e.g. name for containers)
+func (SemanticDiff) schema(res ctlres.Resource) strategicpatch.LookupPatchMeta {
+	obj, err := scheme.Scheme.New(res.GroupVersion().WithKind(res.Kind()))
+	if err != nil {
+		return nil
+	}
+	meta, err := strategicpatch.NewPatchMetaFromStruct(obj)
+	if err != nil {
+		return nil
+	}
+	return meta
+}
+
+// String renders changes in a human readable form, one change per line
+func (cs SemanticChanges) String() string {
+	var lines []string
+
+	for _, c := range cs {
+		path := c.Path
+		if len(path) == 0 {
+			path = "(resource)"
+		}
+
+		switch c.Op {
+		case SemanticChangeOpAdd:
+			lines = append(lines, color.New(color.FgGreen).Sprintf(
+				"+ %s: %s", path, semanticValueString(c.NewValue)))
+		case SemanticChangeOpRemove:
+			lines = append(lines, color.New(color.FgRed).Sprintf(
+				"- %s: %s", path, semanticValueString(c.OldValue)))
+		case SemanticChangeOpReplace:
+			lines = append(lines, color.New(color.FgYellow).Sprintf("~ %s: %s -> %s",
+				path, semanticValueString(c.OldValue), semanticValueString(c.NewValue)))
+		case SemanticChangeOpMove:
+			lines = append(lines, color.New(color.FgCyan).Sprintf(
+				"> %s: moved from position %d to %d", path, c.OldIndex, c.NewIndex))
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func semanticValueString(val interface{}) string {
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(bs)
+}
+
+type semanticPathPart struct {
+	Key   *string
+	Index int
+	// Display is used when showing list items (e.g. [name=app] or [2])
+	Display string
+}
+
+type semanticPath []semanticPathPart
+
+func (p semanticPath) WithKey(key string) semanticPath {
+	return append(append(semanticPath{}, p...), semanticPathPart{Key: &key})
+}
+
+func (p semanticPath) WithItem(idx int, display string) semanticPath {
+	return append(append(semanticPath{}, p...), semanticPathPart{Index: idx, Display: display})
+}
+
+var (
+	semanticPathSimpleKey = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+)
+
+func (p semanticPath) String() string {
+	var result string
+	for _, part := range p {
+		switch {
+		case part.Key == nil:
+			result += part.Display
+		case !semanticPathSimpleKey.MatchString(*part.Key):
+			result += fmt.Sprintf("[%q]", *part.Key)
+		case len(result) == 0:
+			result += *part.Key
+		default:
+			result += "." + *part.Key
+		}
+	}
+	return result
+}
+
+// Matches checks path against rule path (which may include allIndexes parts)
+func (p semanticPath) Matches(rulePath ctlres.Path) bool {
+	if len(p) != len(rulePath) {
+		return false
+	}
+	for i, rulePart := range rulePath {
+		part := p[i]
+		switch {
+		case rulePart.MapKey != nil:
+			if part.Key == nil || *part.Key != *rulePart.MapKey {
+				return false
+			}
+		case rulePart.ArrayIndex != nil:
+			if part.Key != nil {
+				return false
+			}
+			if rulePart.ArrayIndex.Index != nil && *rulePart.ArrayIndex.Index != part.Index {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type semanticDiffCalculator struct {
+	rules []ctlconf.DiffListMergeKeyRule
+}
+
+func (c semanticDiffCalculator) calculate(left, right interface{},
+	path semanticPath, schema strategicpatch.LookupPatchMeta) SemanticChanges {
+
+	switch typedLeft := left.(type) {
+	case map[string]interface{}:
+		if typedRight, ok := right.(map[string]interface{}); ok {
+			return c.calculateMap(typedLeft, typedRight, path, schema)
+		}
+
+	case []interface{}:
+		if typedRight, ok := right.([]interface{}); ok {
+			// Schema for list items is determined by the parent map
+			return c.calculateList(typedLeft, typedRight, path, schema, nil)
+		}
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return nil
+	}
+	return SemanticChanges{{Op: SemanticChangeOpReplace, Path: path.String(), OldValue: left, NewValue: right}}
+}
+
+func (c semanticDiffCalculator) calculateMap(left, right map[string]interface{},
+	path semanticPath, schema strategicpatch.LookupPatchMeta) SemanticChanges {
+
+	var keys []string
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, found := left[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var result SemanticChanges
+
+	for _, k := range keys {
+		keyPath := path.WithKey(k)
+		leftVal, leftFound := left[k]
+		rightVal, rightFound := right[k]
+
+		switch {
+		case leftFound && rightFound:
+			leftList, leftIsList := leftVal.([]interface{})
+			rightList, rightIsList := rightVal.([]interface{})
+
+			if leftIsList && rightIsList {
+				var itemSchema strategicpatch.LookupPatchMeta
+				var schemaMergeKeys []string
+
+				if schema != nil {
+					subschema, patchMeta, err := schema.LookupPatchMetadataForSlice(k)
+					if err == nil {
+						itemSchema = subschema
+						if len(patchMeta.GetPatchMergeKey()) > 0 {
+							schemaMergeKeys = []string{patchMeta.GetPatchMergeKey()}
+						}
+					}
+				}
+
+				result = append(result, c.calculateList(leftList, rightList, keyPath, itemSchema, schemaMergeKeys)...)
+			} else {
+				var subschema strategicpatch.LookupPatchMeta
+				if schema != nil {
+					subschema, _, _ = schema.LookupPatchMetadataForStruct(k)
+				}
+				result = append(result, c.calculate(leftVal, rightVal, keyPath, subschema)...)
+			}
+
+		case leftFound:
+			result = append(result, SemanticChange{Op: SemanticChangeOpRemove, Path: keyPath.String(), OldValue: leftVal})
+		default:
+			result = append(result, SemanticChange{Op: SemanticChangeOpAdd, Path: keyPath.String(), NewValue: rightVal})
+		}
+	}
+
+	return result
+}
+
+type semanticListPair struct {
+	leftIdx, rightIdx int
+}
+
+func (c semanticDiffCalculator) calculateList(left, right []interface{}, path semanticPath,
+	itemSchema strategicpatch.LookupPatchMeta, schemaMergeKeys []string) SemanticChanges {
+
+	mergeKeys := c.mergeKeys(path, schemaMergeKeys)
+
+	var pairs []semanticListPair
+	var displays map[int]string
+	var leftDisplays map[int]string
+
+	leftKeys, leftOk := semanticListItemKeys(left, mergeKeys)
+	rightKeys, rightOk := semanticListItemKeys(right, mergeKeys)
+
+	if len(mergeKeys) > 0 && leftOk && rightOk {
+		pairs = c.pairByKeys(leftKeys, rightKeys)
+		displays, leftDisplays = rightKeys, leftKeys
+	} else {
+		pairs = c.pairByContent(left, right)
+		displays, leftDisplays = semanticListItemIndexes(right), semanticListItemIndexes(left)
+	}
+
+	pairedLeft := map[int]struct{}{}
+	pairedRight := map[int]semanticListPair{}
+	for _, pair := range pairs {
+		pairedLeft[pair.leftIdx] = struct{}{}
+		pairedRight[pair.rightIdx] = pair
+	}
+
+	unmoved := semanticUnmovedPairs(pairs)
+
+	var result SemanticChanges
+
+	for i, item := range left {
+		if _, found := pairedLeft[i]; !found {
+			itemPath := path.WithItem(i, leftDisplays[i])
+			result = append(result, SemanticChange{Op: SemanticChangeOpRemove, Path: itemPath.String(), OldValue: item})
+		}
+	}
+
+	for i, item := range right {
+		itemPath := path.WithItem(i, displays[i])
+
+		pair, found := pairedRight[i]
+		if !found {
+			result = append(result, SemanticChange{Op: SemanticChangeOpAdd, Path: itemPath.String(), NewValue: item})
+			continue
+		}
+		if _, found := unmoved[pair]; !found {
+			result = append(result, SemanticChange{Op: SemanticChangeOpMove,
+				Path: itemPath.String(), OldIndex: pair.leftIdx, NewIndex: pair.rightIdx})
+		}
+		result = append(result, c.calculate(left[pair.leftIdx], item, itemPath, itemSchema)...)
+	}
+
+	return result
+}
+
+// mergeKeys prefers configured rules over keys known from schema
+func (c semanticDiffCalculator) mergeKeys(path semanticPath, schemaMergeKeys []string) []string {
+	for _, rule := range c.rules {
+		if path.Matches(rule.Path) {
+			return rule.MergeKeys
+		}
+	}
+	return schemaMergeKeys
+}
+
+func (semanticDiffCalculator) pairByKeys(leftKeys, rightKeys map[int]string) []semanticListPair {
+	leftIdxByKey := map[string]int{}
+	for i, key := range leftKeys {
+		leftIdxByKey[key] = i
+	}
+
+	var pairs []semanticListPair
+	for i := 0; i < len(rightKeys); i++ {
+		if leftIdx, found := leftIdxByKey[rightKeys[i]]; found {
+			pairs = append(pairs, semanticListPair{leftIdx, i})
+		}
+	}
+	return pairs
+}
+
+// pairByContent first pairs up equal items (to detect moves)
+// and then pairs remaining items by their relative position
+func (semanticDiffCalculator) pairByContent(left, right []interface{}) []semanticListPair {
+	var pairs []semanticListPair
+	pairedLeft := map[int]struct{}{}
+	pairedRight := map[int]struct{}{}
+
+	for i, rightItem := range right {
+		for j, leftItem := range left {
+			if _, found := pairedLeft[j]; found {
+				continue
+			}
+			if reflect.DeepEqual(leftItem, rightItem) {
+				pairs = append(pairs, semanticListPair{j, i})
+				pairedLeft[j] = struct{}{}
+				pairedRight[i] = struct{}{}
+				break
+			}
+		}
+	}
+
+	var unpairedLeft []int
+	for j := range left {
+		if _, found := pairedLeft[j]; !found {
+			unpairedLeft = append(unpairedLeft, j)
+		}
+	}
+
+	for i := range right {
+		if len(unpairedLeft) == 0 {
+			break
+		}
+		if _, found := pairedRight[i]; !found {
+			pairs = append(pairs, semanticListPair{unpairedLeft[0], i})
+			unpairedLeft = unpairedLeft[1:]
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].rightIdx < pairs[j].rightIdx })
+
+	return pairs
+}
+
+// semanticUnmovedPairs returns largest set of pairs that kept their relative order
+// (longest increasing subsequence of left indexes); all other pairs are considered moved
+func semanticUnmovedPairs(pairs []semanticListPair) map[semanticListPair]struct{} {
+	lengths := make([]int, len(pairs))
+	prevs := make([]int, len(pairs))
+	bestIdx := -1
+
+	for i := range pairs {
+		lengths[i] = 1
+		prevs[i] = -1
+		for j := 0; j < i; j++ {
+			if pairs[j].leftIdx < pairs[i].leftIdx && lengths[j]+1 > lengths[i] {
+				lengths[i] = lengths[j] + 1
+				prevs[i] = j
+			}
+		}
+		if bestIdx == -1 || lengths[i] > lengths[bestIdx] {
+			bestIdx = i
+		}
+	}
+
+	result := map[semanticListPair]struct{}{}
+	for i := bestIdx; i >= 0; i = prevs[i] {
+		result[pairs[i]] = struct{}{}
+	}
+	return result
+}
+
+// semanticListItemKeys returns display keys for list items (e.g. [name=app]);
+// returns false if any item does not have all merge keys or if keys are not unique
+func semanticListItemKeys(items []interface{}, mergeKeys []string) (map[int]string, bool) {
+	if len(mergeKeys) == 0 {
+		return nil, false
+	}
+
+	result := map[int]string{}
+	seen := map[string]struct{}{}
+
+	for i, item := range items {
+		typedItem, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		var pieces []string
+		for _, key := range mergeKeys {
+			val, found := typedItem[key]
+			if !found {
+				return nil, false
+			}
+			pieces = append(pieces, fmt.Sprintf("%s=%v", key, val))
+		}
+
+		display := "[" + strings.Join(pieces, ",") + "]"
+		if _, found := seen[display]; found {
+			return nil, false
+		}
+		seen[display] = struct{}{}
+		result[i] = display
+	}
+
+	return result, true
+}
+
+func semanticListItemIndexes(items []interface{}) map[int]string {
+	result := map[int]string{}
+	for i := range items {
+		result[i] = fmt.Sprintf("[%d]", i)
+	}
+	return result
+}
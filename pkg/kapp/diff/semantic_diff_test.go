@@ -0,0 +1,223 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestSemanticDiffWithSchemaMergeKeys(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:1
+        env:
+        - name: A
+          value: "1"
+        - name: B
+          value: "2"
+        - name: C
+          value: "3"
+      - name: sidecar
+        image: busybox
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: busybox
+      - name: app
+        image: nginx:2
+        env:
+        - name: C
+          value: "3"
+        - name: A
+          value: "1"
+        - name: D
+          value: "4"
+`))
+
+	changes := ctldiff.NewSemanticDiff(existingRes, newRes, nil).Changes()
+
+	expectedChanges := ctldiff.SemanticChanges{
+		{
+			Op:       ctldiff.SemanticChangeOpMove,
+			Path:     "spec.template.spec.containers[name=app]",
+			OldIndex: 0,
+			NewIndex: 1,
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpRemove,
+			Path:     "spec.template.spec.containers[name=app].env[name=B]",
+			OldValue: map[string]interface{}{"name": "B", "value": "2"},
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpMove,
+			Path:     "spec.template.spec.containers[name=app].env[name=A]",
+			OldIndex: 0,
+			NewIndex: 1,
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpAdd,
+			Path:     "spec.template.spec.containers[name=app].env[name=D]",
+			NewValue: map[string]interface{}{"name": "D", "value": "4"},
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpReplace,
+			Path:     "spec.template.spec.containers[name=app].image",
+			OldValue: "nginx:1",
+			NewValue: "nginx:2",
+		},
+	}
+	require.Equal(t, expectedChanges, changes)
+}
+
+func TestSemanticDiffWithoutMergeKeys(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: role
+  labels:
+    app.kubernetes.io/name: role
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get]
+- apiGroups: [apps]
+  resources: [deployments]
+  verbs: [get]
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: role
+  labels:
+    app.kubernetes.io/name: role2
+rules:
+- apiGroups: [apps]
+  resources: [deployments]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get, list]
+`))
+
+	changes := ctldiff.NewSemanticDiff(existingRes, newRes, nil).Changes()
+
+	expectedChanges := ctldiff.SemanticChanges{
+		{
+			Op:       ctldiff.SemanticChangeOpReplace,
+			Path:     `metadata.labels["app.kubernetes.io/name"]`,
+			OldValue: "role",
+			NewValue: "role2",
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpMove,
+			Path:     "rules[1]",
+			OldIndex: 0,
+			NewIndex: 1,
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpAdd,
+			Path:     "rules[1].verbs[1]",
+			NewValue: "list",
+		},
+	}
+	require.Equal(t, expectedChanges, changes)
+}
+
+func TestSemanticDiffWithConfiguredMergeKeys(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  stages:
+  - id: build
+    image: builder:1
+  - id: test
+    image: tester:1
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  stages:
+  - id: test
+    image: tester:1
+  - id: build
+    image: builder:2
+`))
+
+	rules := []ctlconf.DiffListMergeKeyRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "example.com/v1", Kind: "Pipeline"},
+		}},
+		Path:      ctlres.NewPathFromStrings([]string{"spec", "stages"}),
+		MergeKeys: []string{"id"},
+	}}
+
+	changes := ctldiff.NewSemanticDiff(existingRes, newRes, rules).Changes()
+
+	expectedChanges := ctldiff.SemanticChanges{
+		{
+			Op:       ctldiff.SemanticChangeOpMove,
+			Path:     "spec.stages[id=build]",
+			OldIndex: 0,
+			NewIndex: 1,
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpReplace,
+			Path:     "spec.stages[id=build].image",
+			OldValue: "builder:1",
+			NewValue: "builder:2",
+		},
+	}
+	require.Equal(t, expectedChanges, changes)
+
+	// Without rule items are paired by content and position
+	changes = ctldiff.NewSemanticDiff(existingRes, newRes, nil).Changes()
+
+	expectedChanges = ctldiff.SemanticChanges{
+		{
+			Op:       ctldiff.SemanticChangeOpMove,
+			Path:     "spec.stages[1]",
+			OldIndex: 0,
+			NewIndex: 1,
+		},
+		{
+			Op:       ctldiff.SemanticChangeOpReplace,
+			Path:     "spec.stages[1].image",
+			OldValue: "builder:1",
+			NewValue: "builder:2",
+		},
+	}
+	require.Equal(t, expectedChanges, changes)
+}
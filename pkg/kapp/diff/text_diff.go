@@ -65,3 +65,92 @@ func (l TextDiff) String(full bool) string {
 
 	return sb.String()
 }
+
+// UnifiedString returns diff in unified format (as produced by diff -u)
+// with given number of context lines around changed lines
+func (l TextDiff) UnifiedString(fromName, toName string, context int) string {
+	if !l.HasChanges() {
+		return ""
+	}
+
+	type line struct {
+		rec         difflib.DiffRecord
+		left, right int // number of preceding lines on each side
+	}
+
+	var lines []line
+	var left, right int
+
+	for _, rec := range l.recs {
+		lines = append(lines, line{rec, left, right})
+		if rec.Delta != difflib.RightOnly {
+			left++
+		}
+		if rec.Delta != difflib.LeftOnly {
+			right++
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for i := 0; i < len(lines); {
+		if lines[i].rec.Delta == difflib.Common {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend hunk while changes are separated by at most 2*context common lines
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].rec.Delta != difflib.Common {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		end += context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		var hunk strings.Builder
+		var leftCount, rightCount int
+
+		for _, ln := range lines[start:end] {
+			switch ln.rec.Delta {
+			case difflib.Common:
+				hunk.WriteString(" " + ln.rec.Payload + "\n")
+				leftCount++
+				rightCount++
+			case difflib.LeftOnly:
+				hunk.WriteString("-" + ln.rec.Payload + "\n")
+				leftCount++
+			case difflib.RightOnly:
+				hunk.WriteString("+" + ln.rec.Payload + "\n")
+				rightCount++
+			}
+		}
+
+		leftStart, rightStart := lines[start].left, lines[start].right
+		if leftCount > 0 {
+			leftStart++
+		}
+		if rightCount > 0 {
+			rightStart++
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", leftStart, leftCount, rightStart, rightCount))
+		sb.WriteString(hunk.String())
+
+		i = end
+	}
+
+	return sb.String()
+}
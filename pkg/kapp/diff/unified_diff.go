@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// UnifiedDiff renders change between resources in unified format
+// so that it could be consumed by patch and code review tools
+type UnifiedDiff struct {
+	existingRes, newRes ctlres.Resource
+	context             int
+}
+
+func NewUnifiedDiff(existingRes, newRes ctlres.Resource, context int) UnifiedDiff {
+	return UnifiedDiff{existingRes, newRes, context}
+}
+
+func (d UnifiedDiff) String() (string, error) {
+	existingLines, err := d.lines(d.existingRes)
+	if err != nil {
+		return "", err
+	}
+
+	newLines, err := d.lines(d.newRes)
+	if err != nil {
+		return "", err
+	}
+
+	context := d.context
+	if context < 0 {
+		context = len(existingLines) + len(newLines)
+	}
+
+	return NewTextDiff(existingLines, newLines, false).UnifiedString(
+		d.fileName("a/", d.existingRes), d.fileName("b/", d.newRes), context), nil
+}
+
+func (UnifiedDiff) lines(res ctlres.Resource) ([]string, error) {
+	if res == nil {
+		return []string{}, nil
+	}
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(bs), "\n"), "\n"), nil
+}
+
+// fileName returns path-like resource identifier (e.g. a/ns/apps/Deployment/app.yml)
+func (UnifiedDiff) fileName(prefix string, res ctlres.Resource) string {
+	if res == nil {
+		return "/dev/null"
+	}
+	var pieces []string
+	// Cluster scoped resources do not have namespace; core resources do not have group
+	for _, piece := range []string{res.Namespace(), res.APIGroup(), res.Kind(), res.Name()} {
+		if len(piece) > 0 {
+			pieces = append(pieces, piece)
+		}
+	}
+	return prefix + strings.Join(pieces, "/") + ".yml"
+}
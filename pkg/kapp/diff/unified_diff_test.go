@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-res
+  namespace: my-ns
+data:
+  a: "1"
+  b: "2"
+  c: "3"
+  d: "4"
+  e: "5"
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-res
+  namespace: my-ns
+data:
+  a: "1"
+  b: "2"
+  c: "30"
+  d: "4"
+  e: "5"
+`))
+
+	actualDiff, err := ctldiff.NewUnifiedDiff(existingRes, newRes, 1).String()
+	require.NoError(t, err)
+
+	expectedDiff := `--- a/my-ns/ConfigMap/my-res.yml
++++ b/my-ns/ConfigMap/my-res.yml
+@@ -4,3 +4,3 @@
+   b: "2"
+-  c: "3"
++  c: "30"
+   d: "4"
+`
+	require.Equal(t, expectedDiff, actualDiff)
+}
+
+func TestUnifiedDiffNewResource(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-ns
+`))
+
+	actualDiff, err := ctldiff.NewUnifiedDiff(nil, newRes, 3).String()
+	require.NoError(t, err)
+
+	expectedDiff := `--- /dev/null
++++ b/Namespace/my-ns.yml
+@@ -0,0 +1,4 @@
++apiVersion: v1
++kind: Namespace
++metadata:
++  name: my-ns
+`
+	require.Equal(t, expectedDiff, actualDiff)
+}
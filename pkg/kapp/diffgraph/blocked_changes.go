@@ -28,10 +28,19 @@ func (c *BlockedChanges) WhyBlocked(changes []*Change) string {
 	var result string
 	for _, change := range changes {
 		result += fmt.Sprintf("%s\n", change.Change.Resource().Description())
-		for _, childChange := range change.WaitingFor {
-			if c.isBlocked(childChange) {
-				result += fmt.Sprintf("  [blocked] %s\n", childChange.Change.Resource().Description())
-			}
+		for _, childChange := range c.BlockedBy(change) {
+			result += fmt.Sprintf("  [blocked] %s\n", childChange.Change.Resource().Description())
+		}
+	}
+	return result
+}
+
+// BlockedBy returns changes that given change is still waiting for
+func (c *BlockedChanges) BlockedBy(change *Change) []*Change {
+	var result []*Change
+	for _, childChange := range change.WaitingFor {
+		if c.isBlocked(childChange) {
+			result = append(result, childChange)
 		}
 	}
 	return result
@@ -78,6 +78,41 @@ func NewChangeGraph(changes []ActualChange,
 	return graph, graph.checkCycles()
 }
 
+// NewChangeGraphFromDeps builds graph with previously calculated
+// dependencies (e.g. loaded from a plan) instead of evaluating change
+// group and rule bindings. waitingFor[i] lists indexes of changes
+// that changes[i] is waiting for.
+func NewChangeGraphFromDeps(changes []ActualChange, waitingFor [][]int, logger logger.Logger) (*ChangeGraph, error) {
+	logger = logger.NewPrefixed("ChangeGraph")
+	defer logger.DebugFunc("NewChangeGraphFromDeps").Finish()
+
+	if len(changes) != len(waitingFor) {
+		return nil, fmt.Errorf("Change graph: Expected number of changes (%d) to match number of dependency lists (%d)",
+			len(changes), len(waitingFor))
+	}
+
+	graphChanges := []*Change{}
+
+	for _, change := range changes {
+		graphChanges = append(graphChanges, &Change{Change: change})
+	}
+
+	for i, idxs := range waitingFor {
+		for _, idx := range idxs {
+			if idx < 0 || idx >= len(graphChanges) || idx == i {
+				return nil, fmt.Errorf("Change graph: Expected change '%s' to wait for valid change, but was '%d'",
+					graphChanges[i].Description(), idx)
+			}
+			graphChanges[i].WaitingFor = append(graphChanges[i].WaitingFor, graphChanges[idx])
+		}
+	}
+
+	graph := &ChangeGraph{graphChanges, logger}
+	graph.dedup()
+
+	return graph, graph.checkCycles()
+}
+
 type sortedRule struct {
 	*Change
 	ChangeRule
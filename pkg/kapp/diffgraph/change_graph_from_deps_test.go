@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestChangeGraphFromDeps(t *testing.T) {
+	// Change rules are ignored since deps are provided explicitly
+	yaml := `
+kind: Namespace
+metadata:
+  name: ns1
+---
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: ns1
+  annotations:
+    kapp.k14s.io/change-rule: "upsert before upserting apps.big.co/job1"
+---
+kind: Job
+metadata:
+  name: job1
+  namespace: ns1
+`
+
+	changes := buildActualChanges(yaml, t)
+
+	graph, err := ctldgraph.NewChangeGraphFromDeps(changes, [][]int{{}, {0}, {0, 1, 1}}, logger.NewTODOLogger())
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) namespace/ns1 () cluster
+(upsert) configmap/cm1 () namespace: ns1
+  (upsert) namespace/ns1 () cluster
+(upsert) job/job1 () namespace: ns1
+  (upsert) namespace/ns1 () cluster
+  (upsert) configmap/cm1 () namespace: ns1
+    (upsert) namespace/ns1 () cluster
+`)
+	require.Equal(t, expectedOutput, output)
+}
+
+func TestChangeGraphFromDepsInvalid(t *testing.T) {
+	yaml := `
+kind: Job
+metadata:
+  name: job1
+---
+kind: Job
+metadata:
+  name: job2
+`
+
+	changes := buildActualChanges(yaml, t)
+
+	_, err := ctldgraph.NewChangeGraphFromDeps(changes, [][]int{{}}, logger.NewTODOLogger())
+	require.EqualError(t, err, "Change graph: Expected number of changes (2) to match number of dependency lists (1)")
+
+	_, err = ctldgraph.NewChangeGraphFromDeps(changes, [][]int{{}, {2}}, logger.NewTODOLogger())
+	require.EqualError(t, err, "Change graph: Expected change '(upsert) job/job2 () cluster' to wait for valid change, but was '2'")
+
+	_, err = ctldgraph.NewChangeGraphFromDeps(changes, [][]int{{1}, {0}}, logger.NewTODOLogger())
+	require.EqualError(t, err, "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)")
+}
+
+func buildActualChanges(resourcesBs string, t *testing.T) []ctldgraph.ActualChange {
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoError(t, err, "Expected resources to parse")
+
+	actualChanges := []ctldgraph.ActualChange{}
+	for _, res := range rs {
+		actualChanges = append(actualChanges, actualChangeFromRes{res, ctldgraph.ActualChangeOpUpsert})
+	}
+	return actualChanges
+}
@@ -16,7 +16,12 @@ const (
   <body>
   	<h1>Changes</h1>
   	<p>Changes are sorted in order that they will be applied. They will be applied in parallel within their group. Each change lists other changes that it will wait for before being applied.</p>
+    <p id="filters"></p>
     <ol id="deps"></ol>
+    <div id="progress">
+      <h1>Progress</h1>
+      <ul id="progress-log"></ul>
+    </div>
   </body>
 </html>
 `
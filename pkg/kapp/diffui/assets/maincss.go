@@ -17,5 +17,20 @@ a {
 
 .highlighted .highlighted { background: #b3e0f7; }
 .highlighted .highlighted-for { background: #f7b3b3; }
+
+.filtered-out { display: none; }
+#filters label { margin-right: 1em; }
+
+pre.diff, pre.yaml { background: #f6f8fa; padding: 5px; margin: 5px 0; }
+.op-create { color: #1a7f37; }
+.op-delete { color: #cf222e; }
+.op-update { color: #9a6700; }
+.strategy { color: #6e7781; }
+
+.status-ok { color: #1a7f37; }
+.status-ongoing, .status-retryable, .status-blocked { color: #9a6700; }
+.status-fail, .status-error, .status-warn { color: #cf222e; }
+
+#progress { display: none; }
 `
 )
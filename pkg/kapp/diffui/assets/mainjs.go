@@ -85,18 +85,149 @@ function DependencyGraph($graph, allChanges, linearizedChangeSections, blockedCh
     } else {
       $change.append("<span>"+change.name+" (0)</span>");
     }
+    if (change.applyOp) {
+      $change.append(buildDetails(change));
+    }
     return $change;
   }
 
+  function buildDetails(change) {
+    var $details = $("<span class='details'/>");
+
+    $details.append(" ").append($("<span class='op'/>").addClass("op-"+change.applyOp).text(change.applyOp));
+    if (change.applyStrategy) {
+      $details.append(" ").append($("<span class='strategy'/>").text("("+change.applyStrategy+")"));
+    }
+    $details.append(" ").append($("<span class='status'/>"));
+    if (change.diff) {
+      $details.append(" <a href='' class='toggle' data-content='diff'>diff</a>");
+    }
+    if (change.yaml) {
+      $details.append(" <a href='' class='toggle' data-content='yaml'>yaml</a>");
+    }
+    return $details;
+  }
+
+  $graph.on("click", "a.toggle", function() {
+    var $change = $(this).closest("li");
+    var content = $(this).attr("data-content");
+    var $content = $change.children("pre."+content);
+
+    if ($content.length > 0) {
+      $content.remove();
+    } else {
+      var change = changeByID[$change.attr("data-change-id")];
+      $content = $("<pre/>").addClass(content).text(change[content]);
+      // keep content above expanded list of changes
+      var $children = $change.children("ul");
+      if ($children.length > 0) {
+        $children.before($content);
+      } else {
+        $change.append($content);
+      }
+    }
+    return false;
+  });
+
+  return {
+    changes: allChanges,
+
+    applyFilters: function(filters) {
+      $("li[data-change-id]", $graph).each(function() {
+        var change = changeByID[$(this).attr("data-change-id")];
+        var matches = true;
+        for (var key in filters) {
+          if (filters[key] != "" && change[key] != filters[key]) {
+            matches = false;
+          }
+        }
+        $(this).toggleClass("filtered-out", !matches);
+      });
+    },
+
+    setStatus: function(changeID, status, state) {
+      var $status = $("li[data-change-id=\""+changeID+"\"] > .details > .status", $graph);
+      $status.text(status).attr("class", "status status-"+state);
+    },
+  };
+}
+
+function Filters($filters, changes, onChange) {
+  var keys = [["kind", "Kind"], ["namespace", "Namespace"], ["applyOp", "Op"]];
+
+  for (var i in keys) {
+    var values = {};
+    for (var j in changes) {
+      values[changes[j][keys[i][0]] || ""] = true;
+    }
+
+    var $select = $("<select/>").attr("data-filter", keys[i][0]);
+    $select.append($("<option value=''/>").text("(all)"));
+    $.each(Object.keys(values).sort(), function(_, val) {
+      if (val != "") {
+        $select.append($("<option/>").attr("value", val).text(val));
+      }
+    });
+    $filters.append($("<label/>").text(keys[i][1]+": ").append($select)).append(" ");
+  }
+
+  $filters.on("change", "select", function() {
+    var filters = {};
+    $("select", $filters).each(function() {
+      filters[$(this).attr("data-filter")] = $(this).val();
+    });
+    onChange(filters);
+  });
+
+  return {};
+}
+
+function ProgressLog($log, graph) {
+  var source = new EventSource("/api/events");
+
+  source.onmessage = function(msg) {
+    var event = JSON.parse(msg.data);
+
+    switch (event.type) {
+    case "section":
+    case "message":
+      $log.append($("<li/>").text(event.message));
+      break;
+    case "apply-start":
+      graph.setStatus(event.changeID, "applying", "ongoing");
+      break;
+    case "apply-finish":
+      graph.setStatus(event.changeID, event.state == "ok" ? "applied" : "apply "+event.state, event.state);
+      break;
+    case "wait-state":
+      graph.setStatus(event.changeID, "wait: "+event.state, event.state);
+      break;
+    case "blocked":
+      graph.setStatus(event.changeID, "blocked", "blocked");
+      break;
+    case "outcome":
+      $log.append($("<li/>").text(event.successful ? "Succeeded" : "Failed: "+(event.message || "")));
+      source.close();
+      break;
+    }
+  };
+
   return {};
 }
 
 $(document).ready(function() {
-  DependencyGraph($("#deps"),
+  var graph = DependencyGraph($("#deps"),
     window.diffData.allChanges || [],
     window.diffData.linearizedChangeSections || [],
     window.diffData.blockedChanges || [],
   );
+
+  Filters($("#filters"), graph.changes, graph.applyFilters);
+
+  if (window.diffData.live) {
+    $("#progress").show();
+    ProgressLog($("#progress-log"), graph);
+  }
 });
 `
 )
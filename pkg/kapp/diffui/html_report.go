@@ -0,0 +1,348 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k14s/difflib"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// HTMLReport renders changes into a static self-contained HTML page
+// (does not reference any external assets) so that it could be
+// stored as an artifact (e.g. in CI) and viewed later.
+type HTMLReport struct {
+	changeViews []ctlcap.ChangeView
+	graph       *ctldgraph.ChangeGraph // optional
+	maskRules   []ctlconf.DiffMaskRule
+	opts        ctldiff.TextDiffViewOpts
+}
+
+func NewHTMLReport(changeViews []ctlcap.ChangeView, graph *ctldgraph.ChangeGraph,
+	maskRules []ctlconf.DiffMaskRule, opts ctldiff.TextDiffViewOpts) *HTMLReport {
+
+	return &HTMLReport{changeViews, graph, maskRules, opts}
+}
+
+func (r *HTMLReport) WriteToFile(path string) error {
+	var buf bytes.Buffer
+
+	err := r.Write(&buf)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, buf.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("Writing diff HTML report: %w", err)
+	}
+
+	return nil
+}
+
+func (r *HTMLReport) Write(w io.Writer) error {
+	data, err := r.data()
+	if err != nil {
+		return err
+	}
+
+	err = htmlReportTemplate.Execute(w, data)
+	if err != nil {
+		return fmt.Errorf("Rendering diff HTML report: %w", err)
+	}
+
+	return nil
+}
+
+type htmlReportData struct {
+	Summary        string
+	Changes        []htmlReportChange
+	HasGraph       bool
+	ChangeSections [][]htmlReportGraphChange
+	BlockedChanges []htmlReportGraphChange
+}
+
+type htmlReportChange struct {
+	ID         string
+	Namespace  string
+	Name       string
+	Kind       string
+	APIVersion string
+	Op         string
+	WaitOp     string
+
+	Description string
+	DiffRows    []htmlReportDiffRow
+}
+
+type htmlReportGraphChange struct {
+	ID          string // empty if change is not included in the report
+	Description string
+	WaitingFor  []string
+}
+
+type htmlReportDiffRow struct {
+	Skipped bool
+
+	LeftNum, RightNum   int
+	Left, Right         string
+	LeftKind, RightKind string // one of common, removed, added, empty
+}
+
+func (r *HTMLReport) data() (htmlReportData, error) {
+	countsView := ctlcap.NewChangesCountsView()
+	changeIDs := map[string]string{}
+	data := htmlReportData{}
+
+	for i, view := range r.changeViews {
+		res := view.Resource()
+		countsView.Add(view.ApplyOp(), view.WaitOp())
+
+		rows, err := r.diffRows(view)
+		if err != nil {
+			return htmlReportData{}, fmt.Errorf("Calculating diff for %s: %w", res.Description(), err)
+		}
+
+		change := htmlReportChange{
+			ID:          fmt.Sprintf("change-%d", i),
+			Namespace:   res.Namespace(),
+			Name:        res.Name(),
+			Kind:        res.Kind(),
+			APIVersion:  res.APIVersion(),
+			Op:          ctlcap.ApplyOpCodeUI(view.ApplyOp()),
+			WaitOp:      ctlcap.WaitOpCodeUI(view.WaitOp()),
+			Description: res.Description(),
+			DiffRows:    rows,
+		}
+
+		changeIDs[ctlres.NewUniqueResourceKey(res).String()] = change.ID
+		data.Changes = append(data.Changes, change)
+	}
+
+	data.Summary = countsView.String()
+
+	if r.graph != nil {
+		data.HasGraph = true
+
+		sections, blockedChanges := r.graph.Linearized()
+		graphChange := func(change *ctldgraph.Change) htmlReportGraphChange {
+			result := htmlReportGraphChange{
+				ID:          changeIDs[ctlres.NewUniqueResourceKey(change.Change.Resource()).String()],
+				Description: change.Description(),
+			}
+			for _, depChange := range change.WaitingFor {
+				result.WaitingFor = append(result.WaitingFor, depChange.Description())
+			}
+			return result
+		}
+
+		for _, section := range sections {
+			var changes []htmlReportGraphChange
+			for _, change := range section {
+				changes = append(changes, graphChange(change))
+			}
+			data.ChangeSections = append(data.ChangeSections, changes)
+		}
+
+		for _, change := range blockedChanges {
+			data.BlockedChanges = append(data.BlockedChanges, graphChange(change))
+		}
+	}
+
+	return data, nil
+}
+
+// diffRows pairs up removed and added lines so that they are shown side by side
+func (r *HTMLReport) diffRows(view ctlcap.ChangeView) ([]htmlReportDiffRow, error) {
+	var textDiff ctldiff.TextDiff
+
+	if r.opts.Mask {
+		var err error
+		textDiff, err = view.ConfigurableTextDiff().Masked(r.maskRules)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		textDiff = view.ConfigurableTextDiff().Full()
+	}
+
+	if !textDiff.HasChanges() {
+		return nil, nil
+	}
+
+	var rows []htmlReportDiffRow
+	var leftOnly, rightOnly []difflib.DiffRecord
+
+	flush := func() {
+		for i := 0; i < len(leftOnly) || i < len(rightOnly); i++ {
+			row := htmlReportDiffRow{LeftKind: "empty", RightKind: "empty"}
+			if i < len(leftOnly) {
+				row.LeftNum, row.Left, row.LeftKind = leftOnly[i].LineLeft, leftOnly[i].Payload, "removed"
+			}
+			if i < len(rightOnly) {
+				row.RightNum, row.Right, row.RightKind = rightOnly[i].LineRight, rightOnly[i].Payload, "added"
+			}
+			rows = append(rows, row)
+		}
+		leftOnly, rightOnly = nil, nil
+	}
+
+	for _, rec := range textDiff.Records() {
+		switch rec.Delta {
+		case difflib.LeftOnly:
+			leftOnly = append(leftOnly, rec)
+		case difflib.RightOnly:
+			rightOnly = append(rightOnly, rec)
+		case difflib.Common:
+			flush()
+			rows = append(rows, htmlReportDiffRow{
+				LeftNum: rec.LineLeft, Left: rec.Payload, LeftKind: "common",
+				RightNum: rec.LineRight, Right: rec.Payload, RightKind: "common",
+			})
+		}
+	}
+	flush()
+
+	return r.rowsInContext(rows), nil
+}
+
+func (r *HTMLReport) rowsInContext(rows []htmlReportDiffRow) []htmlReportDiffRow {
+	if r.opts.Context < 0 {
+		return rows
+	}
+
+	var changedIdxs []int
+	for i, row := range rows {
+		if row.LeftKind != "common" {
+			changedIdxs = append(changedIdxs, i)
+		}
+	}
+
+	inContext := func(i int) bool {
+		for _, changedIdx := range changedIdxs {
+			if i >= changedIdx-r.opts.Context && i <= changedIdx+r.opts.Context {
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []htmlReportDiffRow
+	prevInContext := true
+
+	for i, row := range rows {
+		if inContext(i) {
+			if !prevInContext {
+				result = append(result, htmlReportDiffRow{Skipped: true})
+			}
+			result = append(result, row)
+			prevInContext = true
+		} else {
+			prevInContext = false
+		}
+	}
+	if !prevInContext {
+		result = append(result, htmlReportDiffRow{Skipped: true})
+	}
+
+	return result
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc":  func(i int) int { return i + 1 },
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>kapp - diff report</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+    table { border-collapse: collapse; }
+    th, td { text-align: left; padding: 2px 8px; }
+    table.summary td, table.summary th { border-bottom: 1px solid #d0d7de; }
+    table.diff { width: 100%; table-layout: fixed; font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 12px; margin-bottom: 2em; }
+    table.diff td { white-space: pre-wrap; word-break: break-all; vertical-align: top; }
+    table.diff td.num { width: 3em; color: #6e7781; text-align: right; }
+    td.removed { background: #ffebe9; }
+    td.added { background: #dafbe1; }
+    td.empty { background: #f6f8fa; }
+    tr.skipped td { background: #ddf4ff; color: #6e7781; }
+    .op-delete { color: #cf222e; }
+    .muted { color: #6e7781; }
+  </style>
+</head>
+<body>
+  <h1>Changes</h1>
+  <p>{{ .Summary }}</p>
+
+  <table class="summary">
+    <tr><th>Namespace</th><th>Name</th><th>Kind</th><th>Version</th><th>Op</th><th>Wait to</th></tr>
+    {{- range .Changes }}
+    <tr>
+      <td>{{ if .Namespace }}{{ .Namespace }}{{ else }}(cluster){{ end }}</td>
+      <td>{{ if .DiffRows }}<a href="#{{ .ID }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td>
+      <td>{{ .Kind }}</td>
+      <td>{{ .APIVersion }}</td>
+      <td class="op-{{ .Op }}">{{ .Op }}</td>
+      <td>{{ .WaitOp }}</td>
+    </tr>
+    {{- end }}
+  </table>
+
+  {{- if .HasGraph }}
+  <h2>Apply order</h2>
+  <p class="muted">Changes are applied section by section. Changes within a section are applied in parallel.</p>
+  {{- range $i, $section := .ChangeSections }}
+  <h3>Section {{ inc $i }}</h3>
+  <ul>
+    {{- range $section }}
+    <li>{{ if .ID }}<a href="#{{ .ID }}">{{ .Description }}</a>{{ else }}{{ .Description }}{{ end }}{{ if .WaitingFor }} <span class="muted">(waits for: {{ join .WaitingFor ", " }})</span>{{ end }}</li>
+    {{- end }}
+  </ul>
+  {{- end }}
+
+  <h2>Blocked changes</h2>
+  {{- if .BlockedChanges }}
+  <ul>
+    {{- range .BlockedChanges }}
+    <li>{{ .Description }}{{ if .WaitingFor }} <span class="muted">(waits for: {{ join .WaitingFor ", " }})</span>{{ end }}</li>
+    {{- end }}
+  </ul>
+  {{- else }}
+  <p class="muted">None</p>
+  {{- end }}
+  {{- end }}
+
+  <h2>Diffs</h2>
+  {{- range .Changes }}
+  {{- if .DiffRows }}
+  <h3 id="{{ .ID }}">{{ .Op }} {{ .Description }}</h3>
+  <table class="diff">
+    {{- range .DiffRows }}
+    {{- if .Skipped }}
+    <tr class="skipped"><td class="num"></td><td>...</td><td class="num"></td><td>...</td></tr>
+    {{- else }}
+    <tr>
+      <td class="num">{{ if ne .LeftKind "empty" }}{{ .LeftNum }}{{ end }}</td><td class="{{ .LeftKind }}">{{ .Left }}</td>
+      <td class="num">{{ if ne .RightKind "empty" }}{{ .RightNum }}{{ end }}</td><td class="{{ .RightKind }}">{{ .Right }}</td>
+    </tr>
+    {{- end }}
+    {{- end }}
+  </table>
+  {{- end }}
+  {{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,75 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldiffui "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffui"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestHTMLReport(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+  namespace: my-ns
+stringData:
+  password: old-password
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+  namespace: my-ns
+  labels:
+    app: <app>
+stringData:
+  password: new-password
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+	changes, err := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	require.NoError(t, err)
+
+	var changeViews []ctlcap.ChangeView
+	for _, change := range changes {
+		changeViews = append(changeViews, cmdtools.NewDiffChangeView(change))
+	}
+
+	maskRules := []ctlconf.DiffMaskRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "v1", Kind: "Secret"},
+		}},
+		Path: ctlres.NewPathFromStrings([]string{"stringData"}),
+	}}
+
+	var buf bytes.Buffer
+
+	report := ctldiffui.NewHTMLReport(changeViews, nil, maskRules, ctldiff.TextDiffViewOpts{Context: 2, Mask: true})
+	require.NoError(t, report.Write(&buf))
+
+	out := buf.String()
+
+	require.Contains(t, out, "Op: 0 create, 0 delete, 1 update, 0 noop, 0 exists")
+	require.Contains(t, out, `<h3 id="change-0">update secret/my-secret (v1) namespace: my-ns</h3>`)
+	require.Contains(t, out, `<td class="empty"></td>
+      <td class="num">4</td><td class="added">    app: &lt;app&gt;</td>`)
+	require.Contains(t, out, `<td class="removed">  password: &lt;-- value not shown`)
+	require.Contains(t, out, `<td class="added">  password: &lt;-- value not shown`)
+	require.NotContains(t, out, "old-password")
+	require.NotContains(t, out, "new-password")
+	require.NotContains(t, out, "Apply order")
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// ProgressEvents records apply progress events so that they could be
+// streamed to UI clients (including ones that connect after apply started)
+type ProgressEvents struct {
+	events      []ctlcap.ProgressEvent
+	subscribers map[chan struct{}]struct{}
+	lock        sync.Mutex
+}
+
+var _ ctlcap.ProgressUI = &ProgressEvents{}
+
+func NewProgressEvents() *ProgressEvents {
+	return &ProgressEvents{subscribers: map[chan struct{}]struct{}{}}
+}
+
+func (e *ProgressEvents) NotifyProgress(event ctlcap.ProgressEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.events = append(e.events, event)
+
+	for ch := range e.subscribers {
+		// Subscriber only needs to know that there are new events
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Since returns events recorded after first n events
+func (e *ProgressEvents) Since(n int) []ctlcap.ProgressEvent {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if n >= len(e.events) {
+		return nil
+	}
+	return append([]ctlcap.ProgressEvent{}, e.events[n:]...)
+}
+
+// Subscribe returns channel that receives a value whenever new events are recorded
+func (e *ProgressEvents) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	e.lock.Lock()
+	e.subscribers[ch] = struct{}{}
+	e.lock.Unlock()
+
+	unsubscribe := func() {
+		e.lock.Lock()
+		delete(e.subscribers, ch)
+		e.lock.Unlock()
+	}
+
+	return ch, unsubscribe
+}
+
+// ProgressUI records messages and progress events in addition
+// to forwarding them to the wrapped UI
+type ProgressUI struct {
+	ui     ctlcap.UI
+	events *ProgressEvents
+}
+
+var _ ctlcap.UI = ProgressUI{}
+var _ ctlcap.ProgressUI = ProgressUI{}
+
+func NewProgressUI(ui ctlcap.UI, events *ProgressEvents) ProgressUI {
+	return ProgressUI{ui, events}
+}
+
+func (u ProgressUI) NotifySection(msg string, args ...interface{}) {
+	u.ui.NotifySection(msg, args...)
+	u.events.NotifyProgress(ctlcap.ProgressEvent{
+		Type:    ctlcap.ProgressEventTypeSection,
+		Message: fmt.Sprintf(msg, args...),
+	})
+}
+
+func (u ProgressUI) Notify(msgs []string) {
+	u.ui.Notify(msgs)
+	for _, msg := range msgs {
+		u.events.NotifyProgress(ctlcap.ProgressEvent{
+			Type:    ctlcap.ProgressEventTypeMessage,
+			Message: msg,
+		})
+	}
+}
+
+func (u ProgressUI) NotifyProgress(event ctlcap.ProgressEvent) {
+	if progressUI, ok := u.ui.(ctlcap.ProgressUI); ok {
+		progressUI.NotifyProgress(event)
+	}
+	u.events.NotifyProgress(event)
+}
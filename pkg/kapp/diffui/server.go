@@ -12,12 +12,22 @@ import (
 	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffui/assets"
 )
 
 type ServerOpts struct {
 	DiffDataFunc func() *ctldgraph.ChangeGraph
+
+	MaskRules        []ctlconf.DiffMaskRule
+	TextDiffViewOpts ctldiff.TextDiffViewOpts
+
+	// ProgressEvents are streamed to clients when UI
+	// is running while changes are being applied
+	ProgressEvents *ProgressEvents
 }
 
 type Server struct {
@@ -33,45 +43,158 @@ func (s *Server) Mux() *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.noCacheHandler(s.mainHandler))
 	mux.HandleFunc("/assets/", s.noCacheHandler(s.assetHandler))
+	mux.HandleFunc("/api/changes", s.noCacheHandler(s.changesHandler))
+	mux.HandleFunc("/api/events", s.noCacheHandler(s.eventsHandler))
 	return mux
 }
 
 func (s *Server) Run() error {
-	listener, err := net.Listen("tcp", "localhost:0")
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	return (&http.Server{Handler: s.Mux()}).Serve(listener)
+}
+
+// Start serves UI in the background (e.g. while changes are being applied)
+func (s *Server) Start() error {
+	listener, err := s.listen()
 	if err != nil {
 		return err
 	}
 
+	go func() {
+		// Server keeps running until the process exits
+		_ = (&http.Server{Handler: s.Mux()}).Serve(listener)
+	}()
+
+	return nil
+}
+
+func (s *Server) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+
 	s.ui.BeginLinef("Diff UI server: http://%s\n", listener.Addr())
 
-	return (&http.Server{Handler: s.Mux()}).Serve(listener)
+	return listener, nil
 }
 
 type diffData struct {
 	AllChanges               []diffDataChange `json:"allChanges"`
 	LinearizedChangeSections [][]string       `json:"linearizedChangeSections"`
 	BlockedChanges           []string         `json:"blockedChanges"`
+	// Live indicates that progress events are available
+	Live bool `json:"live"`
 }
 
 type diffDataChange struct {
 	ID            string   `json:"id"`
 	Name          string   `json:"name"`
 	WaitingForIDs []string `json:"waitingForIDs"`
+
+	Resource      string `json:"resource"`
+	Namespace     string `json:"namespace"`
+	Kind          string `json:"kind"`
+	APIVersion    string `json:"apiVersion"`
+	ApplyOp       string `json:"applyOp"`
+	ApplyStrategy string `json:"applyStrategy"`
+	WaitOp        string `json:"waitOp"`
+
+	// Diff is shown in unified format; diff and YAML are masked based on mask rules
+	Diff string `json:"diff"`
+	YAML string `json:"yaml"`
+}
+
+// diffDataProgressEvent associates progress event with a change shown in UI
+type diffDataProgressEvent struct {
+	ChangeID string `json:"changeID,omitempty"`
+	ctlcap.ProgressEvent
 }
 
 func (s *Server) mainHandler(w http.ResponseWriter, _ *http.Request) {
+	dataBs, _ := json.Marshal(s.diffData())
+
+	indexHTML := assets.Files[assets.IndexHTMLPath].Content
+	content := strings.ReplaceAll(indexHTML, assets.IndexHTMLDiffDataJSONMarker, string(dataBs))
+
+	s.write(w, []byte(content))
+}
+
+func (s *Server) changesHandler(w http.ResponseWriter, _ *http.Request) {
+	dataBs, _ := json.Marshal(s.diffData())
+
+	w.Header().Set("Content-Type", "application/json")
+	s.write(w, dataBs)
+}
+
+func (s *Server) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.opts.ProgressEvents == nil {
+		http.Error(w, "Progress events are only available while changes are applied", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+
+	changeIDs := map[string]string{}
+	for _, change := range s.opts.DiffDataFunc().All() {
+		if view, ok := change.Change.(ctlcap.ChangeView); ok {
+			changeIDs[s.changeKey(view.Resource().Description(), view.ApplyOp())] = s.changeID(change)
+		}
+	}
+
+	notifyCh, unsubscribe := s.opts.ProgressEvents.Subscribe()
+	defer unsubscribe()
+
+	var sent int
+
+	for {
+		// Clients that connect later receive all events since the beginning
+		for _, event := range s.opts.ProgressEvents.Since(sent) {
+			ddEvent := diffDataProgressEvent{ProgressEvent: event}
+			if event.Change != nil {
+				ddEvent.ChangeID = changeIDs[s.changeKey(event.Change.Resource, event.Change.ApplyOp)]
+			}
+
+			eventBs, _ := json.Marshal(ddEvent)
+			fmt.Fprintf(w, "data: %s\n\n", eventBs)
+			sent++
+		}
+		flusher.Flush()
+
+		select {
+		case <-notifyCh:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) diffData() diffData {
 	changesGraph := s.opts.DiffDataFunc()
 
 	allChanges := changesGraph.All()
 	linearizedChangeSections, blockedChanges := changesGraph.Linearized()
-	changeID := func(ch *ctldgraph.Change) string { return fmt.Sprintf("ch-%p", ch) }
 
-	diffData := diffData{}
+	diffData := diffData{Live: s.opts.ProgressEvents != nil}
 
 	for _, change := range allChanges {
-		ddChange := diffDataChange{ID: changeID(change), Name: change.Description()}
+		ddChange := diffDataChange{ID: s.changeID(change), Name: change.Description()}
 		for _, depChange := range change.WaitingFor {
-			ddChange.WaitingForIDs = append(ddChange.WaitingForIDs, changeID(depChange))
+			ddChange.WaitingForIDs = append(ddChange.WaitingForIDs, s.changeID(depChange))
+		}
+		if view, ok := change.Change.(ctlcap.ChangeView); ok {
+			s.addChangeDetails(&ddChange, view)
 		}
 		diffData.AllChanges = append(diffData.AllChanges, ddChange)
 	}
@@ -79,21 +202,62 @@ func (s *Server) mainHandler(w http.ResponseWriter, _ *http.Request) {
 	for _, section := range linearizedChangeSections {
 		var changeIDs []string
 		for _, change := range section {
-			changeIDs = append(changeIDs, changeID(change))
+			changeIDs = append(changeIDs, s.changeID(change))
 		}
 		diffData.LinearizedChangeSections = append(diffData.LinearizedChangeSections, changeIDs)
 	}
 
 	for _, change := range blockedChanges {
-		diffData.BlockedChanges = append(diffData.BlockedChanges, changeID(change))
+		diffData.BlockedChanges = append(diffData.BlockedChanges, s.changeID(change))
 	}
 
-	dataBs, _ := json.Marshal(diffData)
+	return diffData
+}
 
-	indexHTML := assets.Files[assets.IndexHTMLPath].Content
-	content := strings.ReplaceAll(indexHTML, assets.IndexHTMLDiffDataJSONMarker, string(dataBs))
+func (s *Server) addChangeDetails(ddChange *diffDataChange, view ctlcap.ChangeView) {
+	res := view.Resource()
 
-	s.write(w, []byte(content))
+	ddChange.Resource = res.Description()
+	ddChange.Namespace = res.Namespace()
+	ddChange.Kind = res.Kind()
+	ddChange.APIVersion = res.APIVersion()
+	ddChange.ApplyOp = ctlcap.ApplyOpCodeUI(view.ApplyOp())
+	ddChange.WaitOp = ctlcap.WaitOpCodeUI(view.WaitOp())
+
+	strategyOp, err := view.ApplyStrategyOp()
+	if err == nil {
+		ddChange.ApplyStrategy = string(strategyOp)
+	}
+
+	existingRes, newRes, err := view.ConfigurableTextDiff().Resources(s.opts.MaskRules, s.opts.TextDiffViewOpts.Mask)
+	if err != nil {
+		ddChange.Diff = fmt.Sprintf("Error: %s", err)
+		return
+	}
+
+	ddChange.Diff, err = ctldiff.NewUnifiedDiff(existingRes, newRes, s.opts.TextDiffViewOpts.Context).String()
+	if err != nil {
+		ddChange.Diff = fmt.Sprintf("Error: %s", err)
+	}
+
+	yamlRes := newRes
+	if yamlRes == nil {
+		yamlRes = existingRes
+	}
+	if yamlRes != nil {
+		yamlBs, err := yamlRes.AsYAMLBytes()
+		if err != nil {
+			ddChange.YAML = fmt.Sprintf("Error: %s", err)
+		} else {
+			ddChange.YAML = string(yamlBs)
+		}
+	}
+}
+
+func (*Server) changeID(change *ctldgraph.Change) string { return fmt.Sprintf("ch-%p", change) }
+
+func (*Server) changeKey(resDesc string, op ctlcap.ClusterChangeApplyOp) string {
+	return string(op) + "|" + resDesc
 }
 
 func (s *Server) assetHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,153 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctldiffui "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffui"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestServerChangesAPI(t *testing.T) {
+	server := httptest.NewServer(newTestServer(t, nil).Mux())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/changes")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var data struct {
+		AllChanges []map[string]interface{} `json:"allChanges"`
+		Live       bool                     `json:"live"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&data))
+
+	require.False(t, data.Live)
+	require.Len(t, data.AllChanges, 1)
+
+	change := data.AllChanges[0]
+	require.Equal(t, "secret/my-secret (v1) namespace: my-ns", change["resource"])
+	require.Equal(t, "Secret", change["kind"])
+	require.Equal(t, "my-ns", change["namespace"])
+	require.Equal(t, "update", change["applyOp"])
+	require.Contains(t, change["diff"], "+    app: new")
+	require.Contains(t, change["yaml"], "app: new")
+	require.NotContains(t, change["diff"], "old-password")
+	require.NotContains(t, change["yaml"], "new-password")
+}
+
+func TestServerEventsAPI(t *testing.T) {
+	events := ctldiffui.NewProgressEvents()
+	server := httptest.NewServer(newTestServer(t, events).Mux())
+	defer server.Close()
+
+	events.NotifyProgress(ctlcap.ProgressEvent{
+		Type: ctlcap.ProgressEventTypeApplyStart,
+		Change: &ctlcap.ProgressEventChange{
+			Resource: "secret/my-secret (v1) namespace: my-ns",
+			ApplyOp:  ctlcap.ClusterChangeApplyOpUpdate,
+		},
+	})
+
+	resp, err := http.Get(server.URL + "/api/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "data: "), "Expected event line, but was: %s", line)
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+
+	require.Equal(t, "apply-start", event["type"])
+	require.True(t, strings.HasPrefix(event["changeID"].(string), "ch-"), "Expected change ID, but was: %#v", event)
+}
+
+func TestServerEventsAPIWithoutProgress(t *testing.T) {
+	server := httptest.NewServer(newTestServer(t, nil).Mux())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type testGraphChange struct {
+	ctlcap.ChangeView
+}
+
+func (testGraphChange) Op() ctldgraph.ActualChangeOp { return ctldgraph.ActualChangeOpUpsert }
+
+func newTestServer(t *testing.T, events *ctldiffui.ProgressEvents) *ctldiffui.Server {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+  namespace: my-ns
+stringData:
+  password: old-password
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+  namespace: my-ns
+  labels:
+    app: new
+stringData:
+  password: new-password
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+	changes, err := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	require.NoError(t, err)
+
+	var graphChanges []ctldgraph.ActualChange
+	for _, change := range changes {
+		graphChanges = append(graphChanges, testGraphChange{cmdtools.NewDiffChangeView(change)})
+	}
+
+	graph, err := ctldgraph.NewChangeGraph(graphChanges, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	maskRules := []ctlconf.DiffMaskRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "v1", Kind: "Secret"},
+		}},
+		Path: ctlres.NewPathFromStrings([]string{"stringData"}),
+	}}
+
+	opts := ctldiffui.ServerOpts{
+		DiffDataFunc:     func() *ctldgraph.ChangeGraph { return graph },
+		MaskRules:        maskRules,
+		TextDiffViewOpts: ctldiff.TextDiffViewOpts{Context: 2, Mask: true},
+		ProgressEvents:   events,
+	}
+	return ctldiffui.NewServer(opts, ui.NewNoopUI())
+}
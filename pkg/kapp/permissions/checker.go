@@ -0,0 +1,169 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/component-helpers/auth/rbac/validation"
+)
+
+// PermissionChecker determines whether current user
+// is allowed to perform an action described by resource attributes.
+// MissingPermissionError is returned if action is not allowed.
+type PermissionChecker interface {
+	Check(context.Context, *authv1.ResourceAttributes) error
+}
+
+// MissingPermissionError indicates that user is not allowed
+// to perform an action described by resource attributes
+type MissingPermissionError struct {
+	Attributes authv1.ResourceAttributes
+}
+
+func (e *MissingPermissionError) Error() string {
+	gvr := schema.GroupVersionResource{
+		Group:    e.Attributes.Group,
+		Version:  e.Attributes.Version,
+		Resource: e.Attributes.Resource,
+	}
+	return fmt.Sprintf("not permitted to %q %s", e.Attributes.Verb, gvr.String())
+}
+
+// MissingPermissions returns all missing permission errors
+// found in a (possibly joined or wrapped) error
+func MissingPermissions(err error) []*MissingPermissionError {
+	var result []*MissingPermissionError
+
+	switch typedErr := err.(type) {
+	case nil:
+		return nil
+	case *MissingPermissionError:
+		return []*MissingPermissionError{typedErr}
+	case interface{ Unwrap() []error }:
+		for _, err := range typedErr.Unwrap() {
+			result = append(result, MissingPermissions(err)...)
+		}
+	default:
+		result = append(result, MissingPermissions(errors.Unwrap(err))...)
+	}
+
+	return result
+}
+
+// AccessReviewChecker issues SelfSubjectAccessReview for each check
+type AccessReviewChecker struct {
+	ssarClient authv1client.SelfSubjectAccessReviewInterface
+}
+
+var _ PermissionChecker = (*AccessReviewChecker)(nil)
+
+func NewAccessReviewChecker(ssarClient authv1client.SelfSubjectAccessReviewInterface) *AccessReviewChecker {
+	return &AccessReviewChecker{ssarClient: ssarClient}
+}
+
+func (c *AccessReviewChecker) Check(ctx context.Context, resourceAttributes *authv1.ResourceAttributes) error {
+	return ValidatePermissions(ctx, c.ssarClient, resourceAttributes)
+}
+
+// RulesReviewChecker fetches rules once per namespace via SelfSubjectRulesReview
+// and evaluates checks locally. Checks that cannot be reliably determined
+// from rules (cluster scoped resources, incomplete rule lists)
+// are delegated to the fallback checker.
+type RulesReviewChecker struct {
+	ssrrClient authv1client.SelfSubjectRulesReviewInterface
+	fallback   PermissionChecker
+
+	rulesByNamespace map[string]rulesReviewResult
+}
+
+type rulesReviewResult struct {
+	rules      []rbacv1.PolicyRule
+	incomplete bool
+}
+
+var _ PermissionChecker = (*RulesReviewChecker)(nil)
+
+func NewRulesReviewChecker(ssrrClient authv1client.SelfSubjectRulesReviewInterface, fallback PermissionChecker) *RulesReviewChecker {
+	return &RulesReviewChecker{
+		ssrrClient:       ssrrClient,
+		fallback:         fallback,
+		rulesByNamespace: map[string]rulesReviewResult{},
+	}
+}
+
+func (c *RulesReviewChecker) Check(ctx context.Context, resourceAttributes *authv1.ResourceAttributes) error {
+	// Rules review always includes namespaced role bindings, hence
+	// it cannot be used to determine access to cluster scoped resources
+	if len(resourceAttributes.Namespace) == 0 {
+		return c.fallback.Check(ctx, resourceAttributes)
+	}
+
+	result, err := c.rulesForNamespace(ctx, resourceAttributes.Namespace)
+	if err != nil {
+		return err
+	}
+
+	resource := resourceAttributes.Resource
+	if len(resourceAttributes.Subresource) > 0 {
+		resource += "/" + resourceAttributes.Subresource
+	}
+
+	requestedRule := rbacv1.PolicyRule{
+		Verbs:     []string{resourceAttributes.Verb},
+		APIGroups: []string{resourceAttributes.Group},
+		Resources: []string{resource},
+	}
+	if len(resourceAttributes.Name) > 0 {
+		requestedRule.ResourceNames = []string{resourceAttributes.Name}
+	}
+
+	if covered, _ := validation.Covers(result.rules, []rbacv1.PolicyRule{requestedRule}); covered {
+		return nil
+	}
+
+	// Some authorizers (e.g. webhooks) are not able to list rules
+	if result.incomplete {
+		return c.fallback.Check(ctx, resourceAttributes)
+	}
+
+	return &MissingPermissionError{Attributes: *resourceAttributes}
+}
+
+func (c *RulesReviewChecker) rulesForNamespace(ctx context.Context, namespace string) (rulesReviewResult, error) {
+	if result, found := c.rulesByNamespace[namespace]; found {
+		return result, nil
+	}
+
+	ssrr := &authv1.SelfSubjectRulesReview{
+		Spec: authv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+
+	retSsrr, err := c.ssrrClient.Create(ctx, ssrr, v1.CreateOptions{})
+	if err != nil {
+		return rulesReviewResult{}, fmt.Errorf("fetching rules for namespace %q: %w", namespace, err)
+	}
+
+	result := rulesReviewResult{incomplete: retSsrr.Status.Incomplete}
+
+	for _, rule := range retSsrr.Status.ResourceRules {
+		result.rules = append(result.rules, rbacv1.PolicyRule{
+			Verbs:         rule.Verbs,
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+		})
+	}
+
+	c.rulesByNamespace[namespace] = result
+
+	return result, nil
+}
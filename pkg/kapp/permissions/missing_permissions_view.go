@@ -0,0 +1,88 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+// MissingPermissionsView collects missing permissions
+// (deduplicated) along with resources that require them
+type MissingPermissionsView struct {
+	keys       []authv1.ResourceAttributes
+	requiredBy map[authv1.ResourceAttributes][]string
+}
+
+func (v *MissingPermissionsView) Add(errs []*MissingPermissionError, res ctlres.Resource) {
+	if v.requiredBy == nil {
+		v.requiredBy = map[authv1.ResourceAttributes][]string{}
+	}
+
+	for _, err := range errs {
+		key := err.Attributes
+		// Version does not affect authorization
+		key.Version = ""
+
+		descs, found := v.requiredBy[key]
+		if !found {
+			v.keys = append(v.keys, key)
+		}
+
+		desc := res.Description()
+		if len(descs) == 0 || descs[len(descs)-1] != desc {
+			v.requiredBy[key] = append(descs, desc)
+		}
+	}
+}
+
+func (v *MissingPermissionsView) Len() int { return len(v.keys) }
+
+func (v *MissingPermissionsView) Print(ui ui.UI) {
+	table := uitable.Table{
+		Title:   "Missing permissions",
+		Content: "permissions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Verb"),
+			uitable.NewHeader("Group"),
+			uitable.NewHeader("Resource"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Required by"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 2, Asc: true},
+			{Column: 3, Asc: true},
+			{Column: 1, Asc: true},
+		},
+	}
+
+	for _, key := range v.keys {
+		resource := key.Resource
+		if len(key.Subresource) > 0 {
+			resource += "/" + key.Subresource
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(key.Namespace),
+			uitable.NewValueString(key.Verb),
+			uitable.NewValueString(key.Group),
+			uitable.NewValueString(resource),
+			uitable.NewValueString(key.Name),
+			uitable.NewValueStrings(v.requiredBy[key]),
+		})
+	}
+
+	table.Notes = []string{fmt.Sprintf("Summary: %d missing permissions", len(v.keys))}
+
+	ui.PrintTable(table)
+}
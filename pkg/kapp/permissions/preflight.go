@@ -6,7 +6,9 @@ package permissions
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/preflight"
@@ -14,18 +16,34 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+const (
+	modeConfigKey = "mode"
+
+	// ModeAccessReview issues SelfSubjectAccessReview for each
+	// required permission and fails with encountered errors
+	ModeAccessReview = "accessReview"
+	// ModeRulesReview fetches rules once per namespace via
+	// SelfSubjectRulesReview, evaluates required permissions
+	// locally and reports all missing permissions
+	ModeRulesReview = "rulesReview"
+)
+
 // Preflight is an implementation of preflight.Check
 // to make it easier to add permission validation
 // as a preflight check
 type Preflight struct {
 	depsFactory cmdcore.DepsFactory
+	ui          ui.UI
 	enabled     bool
+	mode        string
 }
 
-func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) preflight.Check {
+func NewPreflight(depsFactory cmdcore.DepsFactory, ui ui.UI, enabled bool) preflight.Check {
 	return &Preflight{
 		depsFactory: depsFactory,
+		ui:          ui,
 		enabled:     enabled,
+		mode:        ModeAccessReview,
 	}
 }
 
@@ -37,7 +55,26 @@ func (p *Preflight) SetEnabled(enabled bool) {
 	p.enabled = enabled
 }
 
-func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+// SetConfig configures how permissions are evaluated, e.g.
+//
+//	config:
+//	  mode: rulesReview
+func (p *Preflight) SetConfig(config preflight.CheckConfig) error {
+	p.mode = ModeAccessReview
+
+	modeConfig, found := config[modeConfigKey]
+	if !found {
+		return nil
+	}
+
+	switch modeConfig {
+	case ModeAccessReview, ModeRulesReview:
+		p.mode = modeConfig.(string)
+	default:
+		return fmt.Errorf("expected %q config to be one of: %s, %s (given: '%v')",
+			modeConfigKey, ModeAccessReview, ModeRulesReview, modeConfig)
+	}
+
 	return nil
 }
 
@@ -52,9 +89,14 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		return err
 	}
 
-	roleValidator := NewRoleValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), mapper)
-	bindingValidator := NewBindingValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), client.RbacV1(), mapper)
-	basicValidator := NewBasicValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), mapper)
+	var checker PermissionChecker = NewAccessReviewChecker(client.AuthorizationV1().SelfSubjectAccessReviews())
+	if p.mode == ModeRulesReview {
+		checker = NewRulesReviewChecker(client.AuthorizationV1().SelfSubjectRulesReviews(), checker)
+	}
+
+	roleValidator := NewRoleValidator(checker, mapper)
+	bindingValidator := NewBindingValidator(checker, client.RbacV1(), mapper)
+	basicValidator := NewBasicValidator(checker, mapper)
 
 	validator := NewCompositeValidator(basicValidator, map[schema.GroupVersionKind]Validator{
 		rbacv1.SchemeGroupVersion.WithKind("Role"):               roleValidator,
@@ -63,31 +105,49 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"): bindingValidator,
 	})
 
+	missingView := &MissingPermissionsView{}
+
 	errorSet := []error{}
 	for _, change := range changeGraph.All() {
-		switch change.Change.Op() {
-		case ctldgraph.ActualChangeOpDelete:
-			err = validator.Validate(ctx, change.Change.Resource(), "delete")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
-		case ctldgraph.ActualChangeOpUpsert:
-			// Check both create and update permissions
-			err = validator.Validate(ctx, change.Change.Resource(), "create")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
+		res := change.Change.Resource()
 
-			err = validator.Validate(ctx, change.Change.Resource(), "update")
+		for _, verb := range p.requiredVerbs(change.Change.Op()) {
+			err = validator.Validate(ctx, res, verb)
 			if err != nil {
 				errorSet = append(errorSet, err)
+				missingView.Add(MissingPermissions(err), res)
 			}
 		}
 	}
 
-	if len(errorSet) > 0 {
-		return errors.Join(errorSet...)
+	if len(errorSet) == 0 {
+		return nil
 	}
 
-	return nil
+	if p.mode == ModeRulesReview && missingView.Len() > 0 {
+		missingView.Print(p.ui)
+
+		baseErr := fmt.Errorf("missing %d permissions", missingView.Len())
+		return errors.Join(append([]error{baseErr}, errorSet...)...)
+	}
+
+	return errors.Join(errorSet...)
+}
+
+func (p *Preflight) requiredVerbs(op ctldgraph.ActualChangeOp) []string {
+	switch op {
+	case ctldgraph.ActualChangeOpDelete:
+		return []string{"delete"}
+	case ctldgraph.ActualChangeOpUpsert:
+		// Check both create and update permissions
+		verbs := []string{"create", "update"}
+		// Permissions are evaluated locally in rules review mode,
+		// hence it is cheap to include permissions used for patching
+		if p.mode == ModeRulesReview {
+			verbs = append(verbs, "patch")
+		}
+		return verbs
+	default:
+		return nil
+	}
 }
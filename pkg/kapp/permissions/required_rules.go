@@ -0,0 +1,88 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RequiredRules accumulates permissions required to operate on resources
+// grouped by namespace (empty namespace is used for cluster scoped resources)
+type RequiredRules struct {
+	verbsByNs map[string]map[schema.GroupResource]sets.Set[string]
+}
+
+func NewRequiredRules() *RequiredRules {
+	return &RequiredRules{verbsByNs: map[string]map[schema.GroupResource]sets.Set[string]{}}
+}
+
+func (r *RequiredRules) Add(namespace string, gr schema.GroupResource, verbs ...string) {
+	verbsByGR, found := r.verbsByNs[namespace]
+	if !found {
+		verbsByGR = map[schema.GroupResource]sets.Set[string]{}
+		r.verbsByNs[namespace] = verbsByGR
+	}
+
+	if _, found := verbsByGR[gr]; !found {
+		verbsByGR[gr] = sets.New[string]()
+	}
+	verbsByGR[gr].Insert(verbs...)
+}
+
+// Namespaces returns sorted namespaces that have namespaced rules
+func (r *RequiredRules) Namespaces() []string {
+	var result []string
+	for ns := range r.verbsByNs {
+		if len(ns) > 0 {
+			result = append(result, ns)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (r *RequiredRules) HasClusterRules() bool {
+	return len(r.verbsByNs[""]) > 0
+}
+
+// PolicyRules returns rules for a namespace. Resources within
+// the same group that require the same verbs are combined into a single rule.
+func (r *RequiredRules) PolicyRules(namespace string) []rbacv1.PolicyRule {
+	type ruleKey struct {
+		Group string
+		Verbs string
+	}
+
+	resourcesByKey := map[ruleKey][]string{}
+
+	for gr, verbs := range r.verbsByNs[namespace] {
+		key := ruleKey{Group: gr.Group, Verbs: strings.Join(sets.List(verbs), ",")}
+		resourcesByKey[key] = append(resourcesByKey[key], gr.Resource)
+	}
+
+	var rules []rbacv1.PolicyRule
+
+	for key, resources := range resourcesByKey {
+		sort.Strings(resources)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{key.Group},
+			Resources: resources,
+			Verbs:     strings.Split(key.Verbs, ","),
+		})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].APIGroups[0] != rules[j].APIGroups[0] {
+			return rules[i].APIGroups[0] < rules[j].APIGroups[0]
+		}
+		return rules[i].Resources[0] < rules[j].Resources[0]
+	})
+
+	return rules
+}
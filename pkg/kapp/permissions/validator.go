@@ -12,7 +12,6 @@ import (
 	authv1 "k8s.io/api/authorization/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
 )
@@ -25,7 +24,7 @@ type Validator interface {
 // SelfSubjectAccessReview. It returns an error if the SelfSubjectAccessReview indicates that
 // the permissions are not present or are unable to be determined. A nil error is returned if
 // the SelfSubjectAccessReview indicates that the permissions are present.
-// TODO: Look into using SelfSubjectRulesReview instead of SelfSubjectAccessReview
+// See RulesReviewChecker for evaluation based on SelfSubjectRulesReview.
 func ValidatePermissions(ctx context.Context, ssarClient authv1client.SelfSubjectAccessReviewInterface, resourceAttributes *authv1.ResourceAttributes) error {
 	ssar := &authv1.SelfSubjectAccessReview{
 		Spec: authv1.SelfSubjectAccessReviewSpec{
@@ -47,14 +46,7 @@ func ValidatePermissions(ctx context.Context, ssarClient authv1client.SelfSubjec
 	}
 
 	if !retSsar.Status.Allowed {
-		gvr := schema.GroupVersionResource{
-			Group:    resourceAttributes.Group,
-			Version:  resourceAttributes.Version,
-			Resource: resourceAttributes.Resource,
-		}
-		return fmt.Errorf("not permitted to %q %s",
-			resourceAttributes.Verb,
-			gvr.String())
+		return &MissingPermissionError{Attributes: *resourceAttributes}
 	}
 
 	return nil
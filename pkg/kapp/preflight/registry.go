@@ -6,6 +6,7 @@ package preflight
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -20,6 +21,11 @@ type Registry struct {
 	known map[string]Check
 	// Stores the enabled values from the command line
 	enabledFlag map[string]bool
+	// Stores user defined checks from the configuration.
+	// These are always enabled (--preflight flag only
+	// refers to known checks so that it could be validated
+	// when parsed; remove rule from configuration to disable it).
+	custom map[string]Check
 }
 
 // NewRegistry will return a new *Registry with the
@@ -39,9 +45,9 @@ func NewRegistry(checks map[string]Check) *Registry {
 // the pflag.Value interface
 func (c *Registry) String() string {
 	enabled := []string{}
-	for k, v := range c.known {
-		if v.Enabled() {
-			enabled = append(enabled, k)
+	for _, name := range sortedNames(c.known) {
+		if c.known[name].Enabled() {
+			enabled = append(enabled, name)
 		}
 	}
 	return strings.Join(enabled, ",")
@@ -89,11 +95,9 @@ func (c *Registry) Set(s string) error {
 // values. If no values are provided by a user the
 // default values are used.
 func (c *Registry) AddFlags(flags *pflag.FlagSet) {
-	knownChecks := []string{}
-	for name := range c.known {
-		knownChecks = append(knownChecks, name)
-	}
-	flags.Var(c, preflightFlag, fmt.Sprintf("preflight checks to run. Available preflight checks are [%s]", strings.Join(knownChecks, ",")))
+	knownChecks := sortedNames(c.known)
+	flags.Var(c, preflightFlag, fmt.Sprintf("preflight checks to run. Available preflight checks are [%s] "+
+		"(custom checks from configuration always run)", strings.Join(knownChecks, ",")))
 }
 
 // AddCheck adds a new preflight check to the registry.
@@ -111,10 +115,15 @@ func (c *Registry) AddCheck(name string, check Check) {
 // Validate the configuration provided; the rules are:
 // 1. Unknown validator = error
 // 2. Duplicate validator = error
+// 3. Custom validator with the same name as known validator = error
 func (c *Registry) validateConfig(conf []config.PreflightRule) error {
 	haveConfig := map[string]bool{}
 	for _, rule := range conf {
-		if _, ok := c.known[rule.Name]; !ok {
+		_, ok := c.known[rule.Name]
+		if rule.IsCustom() && ok {
+			return fmt.Errorf("custom preflight check in configuration conflicts with built-in check: %q", rule.Name)
+		}
+		if !rule.IsCustom() && !ok {
 			return fmt.Errorf("unknown preflight check in configuration: %q", rule.Name)
 		}
 		if _, ok := haveConfig[rule.Name]; ok {
@@ -133,7 +142,12 @@ func (c *Registry) SetConfig(conf []config.PreflightRule) error {
 	}
 	// map the configuration by name
 	config := map[string]map[string]any{}
+	c.custom = map[string]Check{}
 	for _, rule := range conf {
+		if rule.IsCustom() {
+			c.custom[rule.Name] = NewRuleCheck(rule)
+			continue
+		}
 		config[rule.Name] = rule.Config
 	}
 	if len(c.enabledFlag) == 0 {
@@ -152,11 +166,13 @@ func (c *Registry) SetConfig(conf []config.PreflightRule) error {
 	return nil
 }
 
-// Run will execute any enabled preflight checks. The provided
-// Context and ChangeGraph will be passed to the preflight checks
-// that are being executed.
+// Run will execute any enabled preflight checks, followed by
+// enabled custom checks from the configuration (each in order of
+// their names). The provided Context and ChangeGraph will be passed
+// to the preflight checks that are being executed.
 func (c *Registry) Run(ctx context.Context, cg *ctldgraph.ChangeGraph) error {
-	for name, check := range c.known {
+	for _, name := range sortedNames(c.known) {
+		check := c.known[name]
 		if check.Enabled() {
 			err := check.Run(ctx, cg)
 			if err != nil {
@@ -164,5 +180,23 @@ func (c *Registry) Run(ctx context.Context, cg *ctldgraph.ChangeGraph) error {
 			}
 		}
 	}
+	for _, name := range sortedNames(c.custom) {
+		check := c.custom[name]
+		if check.Enabled() {
+			err := check.Run(ctx, cg)
+			if err != nil {
+				return fmt.Errorf("running custom preflight check %q: %w", name, err)
+			}
+		}
+	}
 	return nil
 }
+
+func sortedNames(checks map[string]Check) []string {
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -124,6 +124,34 @@ func TestRegistryRun(t *testing.T) {
 	}
 }
 
+func TestRegistryRunOrder(t *testing.T) {
+	var ran []string
+	newCheck := func(name string) Check {
+		return NewCheck(func(_ context.Context, _ *diffgraph.ChangeGraph, _ CheckConfig) error {
+			ran = append(ran, name)
+			return nil
+		}, nil, true)
+	}
+
+	registry := &Registry{
+		known: map[string]Check{
+			"cCheck": newCheck("cCheck"),
+			"aCheck": newCheck("aCheck"),
+			"bCheck": newCheck("bCheck"),
+		},
+		custom: map[string]Check{
+			"zCustom": newCheck("zCustom"),
+			"yCustom": newCheck("yCustom"),
+		},
+	}
+
+	for i := 0; i < 5; i++ {
+		ran = nil
+		require.NoError(t, registry.Run(nil, nil))
+		require.Equal(t, []string{"aCheck", "bCheck", "cCheck", "yCustom", "zCustom"}, ran)
+	}
+}
+
 func TestRegistryConfig(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -333,3 +361,77 @@ preflightRules:
 		})
 	}
 }
+
+func TestRegistryEnableCustom(t *testing.T) {
+	configYaml := `---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: someCustom
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          return {"passed": True}
+        end
+- name: otherCustom
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          return {"passed": True}
+        end
+`
+
+	testCases := []struct {
+		name      string
+		cmdEnable string
+		results   map[string]bool
+		shouldErr bool
+	}{
+		{
+			name:    "no command line, custom checks enabled",
+			results: map[string]bool{"someCustom": true, "otherCustom": true},
+		},
+		{
+			name:      "built-in check listed on command line, custom checks enabled",
+			cmdEnable: "someCheck",
+			results:   map[string]bool{"someCustom": true, "otherCustom": true},
+		},
+		{
+			name:      "custom check listed on command line, error returned",
+			cmdEnable: "someCustom",
+			shouldErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := &Registry{
+				known: map[string]Check{
+					"someCheck": NewCheck(nil, nil, false),
+				},
+				enabledFlag: map[string]bool{},
+			}
+			if tc.cmdEnable != "" {
+				err := registry.Set(tc.cmdEnable)
+				require.Equalf(t, tc.shouldErr, err != nil, "Unexpected error from Set(): %v", err)
+				if err != nil {
+					return
+				}
+			}
+
+			configRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(configYaml))).Resources()
+			require.NoErrorf(t, err, "Parsing resources")
+			_, conf, err := ctlconf.NewConfFromResources(configRs)
+			require.NoErrorf(t, err, "Parsing config")
+
+			err = registry.SetConfig(conf.PreflightRules())
+			require.NoErrorf(t, err, "Unexpected error from SetConfig(): %v", err)
+
+			for k, v := range tc.results {
+				require.Equalf(t, v, registry.custom[k].Enabled(), "Unexpected enable value for %s", k)
+			}
+		})
+	}
+}
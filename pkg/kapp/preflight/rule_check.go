@@ -0,0 +1,102 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	RuleChangeOpCreate = "create"
+	RuleChangeOpUpdate = "update"
+	RuleChangeOpDelete = "delete"
+	RuleChangeOpNoop   = "noop"
+)
+
+// ChangeWithResources is implemented by changes that
+// provide both new and existing resources (e.g. cluster changes)
+type ChangeWithResources interface {
+	NewResource() ctlres.Resource
+	ExistingResource() ctlres.Resource
+}
+
+// NewRuleCheck returns preflight check that evaluates
+// user defined rule against each matching change
+func NewRuleCheck(rule config.PreflightRule) Check {
+	checkFunc := func(_ context.Context, changeGraph *ctldgraph.ChangeGraph, _ CheckConfig) error {
+		return runRule(rule, changeGraph)
+	}
+	return NewCheck(checkFunc, nil, true)
+}
+
+func runRule(rule config.PreflightRule, changeGraph *ctldgraph.ChangeGraph) error {
+	contract := RuleContractV1{Starlark: rule.Ytt.FuncContractV1.Rule}
+	matcher := rule.ResourceMatcher()
+
+	var failedErrs []error
+
+	for _, change := range changeGraph.All() {
+		res := change.Change.Resource()
+		if !matcher.Matches(res) {
+			continue
+		}
+
+		result, err := contract.Apply(newRuleContractV1Change(change.Change))
+		if err != nil {
+			return fmt.Errorf("Evaluating rule for resource '%s': %w", res.Description(), err)
+		}
+
+		if !result.Passed {
+			msg := result.Message
+			if len(msg) == 0 {
+				msg = "no message provided"
+			}
+			failedErrs = append(failedErrs, fmt.Errorf("%s: %s", res.Description(), msg))
+		}
+	}
+
+	if len(failedErrs) > 0 {
+		baseErr := fmt.Errorf("rule %q failed for %d changes", rule.Name, len(failedErrs))
+		return errors.Join(append([]error{baseErr}, failedErrs...)...)
+	}
+	return nil
+}
+
+func newRuleContractV1Change(change ctldgraph.ActualChange) RuleContractV1Change {
+	var newRes, existingRes ctlres.Resource
+
+	if changeWithRs, ok := change.(ChangeWithResources); ok {
+		newRes = changeWithRs.NewResource()
+		existingRes = changeWithRs.ExistingResource()
+	} else {
+		switch change.Op() {
+		case ctldgraph.ActualChangeOpDelete:
+			existingRes = change.Resource()
+		default:
+			newRes = change.Resource()
+		}
+	}
+
+	var op string
+
+	switch change.Op() {
+	case ctldgraph.ActualChangeOpUpsert:
+		op = RuleChangeOpUpdate
+		if existingRes == nil {
+			op = RuleChangeOpCreate
+		}
+	case ctldgraph.ActualChangeOpDelete:
+		op = RuleChangeOpDelete
+	default:
+		op = RuleChangeOpNoop
+	}
+
+	return NewRuleContractV1Change(op, newRes, existingRes)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+package preflight
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestRuleCheck(t *testing.T) {
+	configYaml := `---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: noLoadBalancers
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          if change.op in ["create", "update"] and change.newResource["spec"]["type"] == "LoadBalancer":
+            return {"passed": False, "message": "LoadBalancer services are not allowed"}
+          end
+          return {"passed": True}
+        end
+- name: noPVCDeletes
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          if change.op == "delete" and change.existingResource["kind"] == "PersistentVolumeClaim":
+            return {"passed": False, "message": "deleting PVCs is not allowed"}
+          end
+          return {"passed": True}
+        end
+`
+
+	testCases := []struct {
+		name        string
+		resources   string
+		op          ctldgraph.ActualChangeOp
+		expectedErr string
+	}{
+		{
+			name: "allowed service type",
+			resources: `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: ClusterIP
+`,
+			op: ctldgraph.ActualChangeOpUpsert,
+		},
+		{
+			name: "disallowed service type",
+			resources: `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: LoadBalancer
+`,
+			op:          ctldgraph.ActualChangeOpUpsert,
+			expectedErr: "LoadBalancer services are not allowed",
+		},
+		{
+			name: "disallowed delete",
+			resources: `---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+`,
+			op:          ctldgraph.ActualChangeOpDelete,
+			expectedErr: "deleting PVCs is not allowed",
+		},
+		{
+			name: "allowed noop",
+			resources: `---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+`,
+			op: ctldgraph.ActualChangeOpNoop,
+		},
+	}
+
+	configRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(configYaml))).Resources()
+	require.NoErrorf(t, err, "Parsing resources")
+
+	_, conf, err := ctlconf.NewConfFromResources(configRs)
+	require.NoErrorf(t, err, "Parsing config")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistry(map[string]Check{})
+			require.NoError(t, registry.SetConfig(conf.PreflightRules()))
+
+			rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(tc.resources))).Resources()
+			require.NoErrorf(t, err, "Parsing resources")
+
+			var changes []ctldgraph.ActualChange
+			for _, res := range rs {
+				changes = append(changes, actualChangeFromRes{res, tc.op})
+			}
+
+			graph, err := ctldgraph.NewChangeGraph(changes, nil, nil, logger.NewTODOLogger())
+			require.NoError(t, err)
+
+			err = registry.Run(context.Background(), graph)
+			if len(tc.expectedErr) > 0 {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRuleCheckConfig(t *testing.T) {
+	configYaml := `---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: someCheck
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          return {"passed": True}
+        end
+`
+
+	configRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(configYaml))).Resources()
+	require.NoErrorf(t, err, "Parsing resources")
+
+	_, conf, err := ctlconf.NewConfFromResources(configRs)
+	require.NoErrorf(t, err, "Parsing config")
+
+	registry := NewRegistry(map[string]Check{"someCheck": NewCheck(nil, nil, false)})
+
+	err = registry.SetConfig(conf.PreflightRules())
+	require.ErrorContains(t, err, "conflicts with built-in check")
+}
+
+type actualChangeFromRes struct {
+	res ctlres.Resource
+	op  ctldgraph.ActualChangeOp
+}
+
+func (a actualChangeFromRes) Resource() ctlres.Resource    { return a.res }
+func (a actualChangeFromRes) Op() ctldgraph.ActualChangeOp { return a.op }
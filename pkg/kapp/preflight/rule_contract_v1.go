@@ -0,0 +1,97 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"fmt"
+
+	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
+	"github.com/k14s/ytt/pkg/cmd/ui"
+	"github.com/k14s/ytt/pkg/files"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+// RuleContractV1 evaluates user provided Starlark function
+// named 'check' against a single change. Function receives
+// struct with op, newResource and existingResource attributes.
+type RuleContractV1 struct {
+	Starlark string
+}
+
+type RuleContractV1Change struct {
+	Op               string                 `json:"op"`
+	NewResource      map[string]interface{} `json:"newResource"`
+	ExistingResource map[string]interface{} `json:"existingResource"`
+}
+
+type ruleContractV1Result struct {
+	Result RuleContractV1ResultDetails
+}
+
+type RuleContractV1ResultDetails struct {
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+func NewRuleContractV1Change(op string, newRes, existingRes ctlres.Resource) RuleContractV1Change {
+	change := RuleContractV1Change{Op: op}
+	if newRes != nil {
+		change.NewResource = newRes.DeepCopyRaw()
+	}
+	if existingRes != nil {
+		change.ExistingResource = existingRes.DeepCopyRaw()
+	}
+	return change
+}
+
+func (t RuleContractV1) Apply(change RuleContractV1Change) (*RuleContractV1ResultDetails, error) {
+	opts := cmdtpl.NewOptions()
+
+	opts.DataValuesFlags.FromFiles = []string{"values.yml"}
+	opts.DataValuesFlags.ReadFileFunc = func(path string) ([]byte, error) {
+		if path != "values.yml" {
+			return nil, fmt.Errorf("Unknown file to read: %s", path)
+		}
+		return yaml.Marshal(change)
+	}
+
+	filesToProcess := []*files.File{
+		files.MustNewFileFromSource(files.NewBytesSource("rule.star", []byte(t.Starlark))),
+		files.MustNewFileFromSource(files.NewBytesSource("config.yml", t.getConfigYAML())),
+	}
+
+	out := opts.RunWithFiles(cmdtpl.Input{Files: filesToProcess}, ui.NewTTY(false))
+	if out.Err != nil {
+		return nil, fmt.Errorf("Evaluating: %w", out.Err)
+	}
+
+	if len(out.Files) == 0 {
+		return nil, fmt.Errorf("Expected to find config.yml but saw zero files")
+	}
+
+	file := out.Files[0]
+	if file.RelativePath() != "config.yml" {
+		return nil, fmt.Errorf("Expected config.yml but was: %s", file.RelativePath())
+	}
+
+	configObj := ruleContractV1Result{}
+
+	err := yaml.Unmarshal(file.Bytes(), &configObj)
+	if err != nil {
+		return nil, fmt.Errorf("Deserializing result: %w", err)
+	}
+
+	return &configObj.Result, nil
+}
+
+func (t RuleContractV1) getConfigYAML() []byte {
+	config := `
+#@ load("rule.star", "check")
+#@ load("@ytt:data", "data")
+
+result: #@ check(data.values)
+`
+	return []byte(config)
+}
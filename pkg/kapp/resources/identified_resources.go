@@ -26,6 +26,21 @@ func NewIdentifiedResources(coreClient kubernetes.Interface, resourceTypes Resou
 		fallbackAllowedNamespaces, logger.NewPrefixed("IdentifiedResources")}
 }
 
+type dryRunResources interface {
+	WithDryRun() Resources
+}
+
+// WithDryRun returns identified resources that send all mutating
+// requests as server-side dry runs
+func (r IdentifiedResources) WithDryRun() (IdentifiedResources, error) {
+	resources, ok := r.resources.(dryRunResources)
+	if !ok {
+		return IdentifiedResources{}, fmt.Errorf("Expected resources to support server-side dry run")
+	}
+	r.resources = resources.WithDryRun()
+	return r, nil
+}
+
 func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Create(%s)", resource.Description())).Finish()
 
@@ -72,6 +87,29 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 	return resource, nil
 }
 
+func (r IdentifiedResources) Apply(resource Resource, opts ApplyOpts) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("Apply(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err = r.resources.Apply(resource, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
 func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Patch(%s)", resource.Description())).Finish()
 	return r.resources.Patch(resource, patchType, data)
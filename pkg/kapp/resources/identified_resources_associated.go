@@ -0,0 +1,84 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AssociatedResourcesQuery selects resources that are associated with a resource
+// by means other than kapp association label (e.g. resources created
+// by an operator for a custom resource). Only one of OwnerReference,
+// LabelSelector or Name is expected to be set.
+type AssociatedResourcesQuery struct {
+	GroupKind schema.GroupKind
+
+	OwnerReference bool
+	LabelSelector  labels.Selector
+	Name           string
+}
+
+// ListAssociated returns resources matching queries. Namespaced resources
+// are looked up in the namespace of given resource (or in all namespaces
+// if resource is cluster scoped); queries by name are scoped to that name.
+func (r IdentifiedResources) ListAssociated(resource Resource, queries []AssociatedResourcesQuery) ([]Resource, error) {
+	defer r.logger.DebugFunc("ListAssociated").Finish()
+
+	var result []Resource
+	var nsScope []string
+
+	if len(resource.Namespace()) > 0 {
+		nsScope = []string{resource.Namespace()}
+	}
+
+	for _, query := range queries {
+		labelSelector := query.LabelSelector
+		if labelSelector == nil {
+			labelSelector = labels.Everything()
+		}
+
+		listOpts := IdentifiedResourcesListOpts{
+			GKsScope:           []schema.GroupKind{query.GroupKind},
+			ResourceNamespaces: nsScope,
+			Namespace:          resource.Namespace(),
+		}
+		if len(query.Name) > 0 {
+			listOpts.FieldSelector = fields.OneTermEqualSelector("metadata.name", query.Name)
+		}
+
+		resources, err := r.List(labelSelector, nil, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, res := range resources {
+			if query.matches(resource, res) {
+				result = append(result, res)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (q AssociatedResourcesQuery) matches(resource, candidate Resource) bool {
+	// Cluster scoped candidates (e.g. storage classes) are not namespace bound
+	if len(resource.Namespace()) > 0 && len(candidate.Namespace()) > 0 && candidate.Namespace() != resource.Namespace() {
+		return false
+	}
+	if len(q.Name) > 0 && candidate.Name() != q.Name {
+		return false
+	}
+	if q.OwnerReference {
+		for _, ref := range candidate.OwnerRefs() {
+			if string(ref.UID) == resource.UID() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
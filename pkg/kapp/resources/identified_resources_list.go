@@ -7,6 +7,7 @@ import (
 	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -15,6 +16,10 @@ type IdentifiedResourcesListOpts struct {
 	IgnoreCachedResTypes bool
 	GKsScope             []schema.GroupKind
 	ResourceNamespaces   []string
+
+	// FieldSelector and Namespace (if specified) narrow down listed resources
+	FieldSelector fields.Selector
+	Namespace     string
 }
 
 func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []ResourceRef, opts IdentifiedResourcesListOpts) ([]Resource, error) {
@@ -55,6 +60,11 @@ func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []Resou
 			LabelSelector: labelSelector.String(),
 		},
 		ResourceNamespaces: opts.ResourceNamespaces,
+		Namespace:          opts.Namespace,
+	}
+
+	if opts.FieldSelector != nil {
+		allOpts.ListOpts.FieldSelector = opts.FieldSelector.String()
 	}
 
 	resources, err := r.resources.All(resTypes, allOpts)
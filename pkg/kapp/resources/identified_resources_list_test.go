@@ -66,6 +66,9 @@ func (r *FakeResources) Patch(ctlres.Resource, types.PatchType, []byte) (ctlres.
 }
 func (r *FakeResources) Update(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
 func (r *FakeResources) Create(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
+func (r *FakeResources) Apply(ctlres.Resource, ctlres.ApplyOpts) (ctlres.Resource, error) {
+	return nil, nil
+}
 
 type FakeResourceTypes struct{}
 
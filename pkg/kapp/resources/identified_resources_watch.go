@@ -0,0 +1,239 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	watchRestartInterval    = 1 * time.Second
+	watchRestartMaxInterval = 30 * time.Second
+)
+
+type WatchOpts struct {
+	GroupKinds   []schema.GroupKind
+	ResourceRefs []ResourceRef
+	Namespaces   []string
+
+	// Name and LabelSelector (if specified) narrow down
+	// watched resources to avoid receiving unrelated events
+	Name          string
+	LabelSelector labels.Selector
+}
+
+// Matches returns true if resource is of one of the GroupKinds
+// and satisfies namespace, name and label selector scoping
+func (o WatchOpts) Matches(res Resource) bool {
+	var gkFound bool
+	for _, gk := range o.GroupKinds {
+		if gk == res.GroupKind() {
+			gkFound = true
+			break
+		}
+	}
+	if !gkFound {
+		return false
+	}
+	if len(o.Namespaces) > 0 && len(res.Namespace()) > 0 {
+		var nsFound bool
+		for _, ns := range o.Namespaces {
+			if ns == res.Namespace() {
+				nsFound = true
+				break
+			}
+		}
+		if !nsFound {
+			return false
+		}
+	}
+	if len(o.Name) > 0 && o.Name != res.Name() {
+		return false
+	}
+	if o.LabelSelector != nil && !o.LabelSelector.Matches(labels.Set(res.Labels())) {
+		return false
+	}
+	return true
+}
+
+func (o WatchOpts) listOpts() metav1.ListOptions {
+	var listOpts metav1.ListOptions
+	if len(o.Name) > 0 {
+		listOpts.FieldSelector = fields.OneTermEqualSelector("metadata.name", o.Name).String()
+	}
+	if o.LabelSelector != nil {
+		listOpts.LabelSelector = o.LabelSelector.String()
+	}
+	return listOpts
+}
+
+type watchableResources interface {
+	Watch(resType ResourceType, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// Watch starts watching resources matching given GroupKinds or resource refs
+// (scoped by name and label selector if specified) and sends them over
+// resourcesCh whenever they are added, modified or deleted.
+// It returns once all watches are established (or any one of them fails,
+// e.g. due to lack of permissions); events are sent until cancelCh is closed.
+func (r IdentifiedResources) Watch(opts WatchOpts, resourcesCh chan<- Resource, cancelCh chan struct{}) error {
+	defer r.logger.DebugFunc("Watch").Finish()
+
+	resources, ok := r.resources.(watchableResources)
+	if !ok {
+		return fmt.Errorf("Expected resources to support watching")
+	}
+
+	resTypes, err := r.resourceTypes.All(false)
+	if err != nil {
+		return err
+	}
+
+	resTypes = Watchable(resTypes)
+	resTypes = append(MatchingAnyGK(resTypes, opts.GroupKinds), MatchingAny(resTypes, opts.ResourceRefs)...)
+
+	namespaces := uniqAndValidNamespaces(opts.Namespaces)
+	if len(namespaces) == 0 {
+		namespaces = []string{""} // all namespaces
+	}
+
+	listOpts := opts.listOpts()
+
+	var watchers []resourceWatcher
+	seenGRs := map[schema.GroupResource]struct{}{}
+
+	for _, resType := range resTypes {
+		// Same resources may be served via multiple versions
+		if _, found := seenGRs[resType.GroupResource()]; found {
+			continue
+		}
+		seenGRs[resType.GroupResource()] = struct{}{}
+
+		resTypeNamespaces := []string{""}
+		if resType.Namespaced() {
+			resTypeNamespaces = namespaces
+		}
+
+		for _, ns := range resTypeNamespaces {
+			watcher, err := resources.Watch(resType, ns, listOpts)
+			if err != nil {
+				for _, w := range watchers {
+					w.watcher.Stop()
+				}
+				return err
+			}
+			watchers = append(watchers, resourceWatcher{resType, ns, listOpts, watcher, resources, r.logger})
+		}
+	}
+
+	for _, w := range watchers {
+		go w.forward(resourcesCh, cancelCh)
+	}
+
+	return nil
+}
+
+type resourceWatcher struct {
+	resType   ResourceType
+	namespace string
+	listOpts  metav1.ListOptions
+	watcher   watch.Interface
+	resources watchableResources
+	logger    logger.Logger
+}
+
+func (w resourceWatcher) forward(resourcesCh chan<- Resource, cancelCh chan struct{}) {
+	defer func() { w.watcher.Stop() }()
+
+	// Resume from last seen resource version when restarting
+	// so that events that happened in between are not missed
+	var lastResourceVersion string
+
+	for {
+		select {
+		case e, ok := <-w.watcher.ResultChan():
+			if !ok || e.Object == nil {
+				// Watcher may expire, hence try to restart it;
+				// missed events are picked up by periodic checks
+				w.watcher.Stop()
+
+				watcher, ok := w.restart(lastResourceVersion, cancelCh)
+				if !ok {
+					return
+				}
+				w.watcher = watcher
+				continue
+			}
+
+			switch e.Type {
+			case watch.Error:
+				// Last seen resource version may be too old (e.g. compacted),
+				// hence start over from the current list when restarting
+				lastResourceVersion = ""
+
+			case watch.Bookmark:
+				if obj, ok := e.Object.(metav1.Object); ok {
+					lastResourceVersion = obj.GetResourceVersion()
+				}
+
+			case watch.Added, watch.Modified, watch.Deleted:
+				un, ok := e.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				lastResourceVersion = un.GetResourceVersion()
+				select {
+				case resourcesCh <- NewResourceUnstructured(*un, w.resType):
+				case <-cancelCh:
+					return
+				}
+			}
+
+		case <-cancelCh:
+			return
+		}
+	}
+}
+
+// restart retries watching with backoff until it succeeds
+// or cancelCh is closed (false is returned in that case)
+func (w resourceWatcher) restart(lastResourceVersion string, cancelCh chan struct{}) (watch.Interface, bool) {
+	interval := watchRestartInterval
+
+	for {
+		select {
+		case <-time.After(interval):
+		case <-cancelCh:
+			return nil, false
+		}
+
+		listOpts := w.listOpts
+		listOpts.ResourceVersion = lastResourceVersion
+
+		watcher, err := w.resources.Watch(w.resType, w.namespace, listOpts)
+		if err == nil {
+			return watcher, true
+		}
+
+		interval *= 2
+		if interval > watchRestartMaxInterval {
+			interval = watchRestartMaxInterval
+		}
+
+		w.logger.Info("Restarting watch of %s in namespace '%s' failed, falling back to polling "+
+			"until it succeeds (retrying in %s): %s", w.resType.GroupVersionResource.String(), w.namespace, interval, err)
+
+		// Last seen resource version may be the reason for failure
+		lastResourceVersion = ""
+	}
+}
@@ -212,6 +212,10 @@ func (p ResourceType) Listable() bool {
 	return p.containsStr(p.APIResource.Verbs, "list")
 }
 
+func (p ResourceType) Watchable() bool {
+	return p.containsStr(p.APIResource.Verbs, "watch")
+}
+
 func (p ResourceType) Deletable() bool {
 	return p.containsStr(p.APIResource.Verbs, "delete")
 }
@@ -235,6 +239,16 @@ func Listable(in []ResourceType) []ResourceType {
 	return out
 }
 
+func Watchable(in []ResourceType) []ResourceType {
+	var out []ResourceType
+	for _, item := range in {
+		if item.Watchable() {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func Matching(in []ResourceType, ref ResourceRef) []ResourceType {
 	partResourceRef := PartialResourceRef{ref.GroupVersionResource}
 	var out []ResourceType
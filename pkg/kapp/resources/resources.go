@@ -5,6 +5,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -18,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
@@ -46,12 +48,18 @@ type Resources interface {
 	Patch(Resource, types.PatchType, []byte) (Resource, error)
 	Update(Resource) (Resource, error)
 	Create(resource Resource) (Resource, error)
+	Apply(Resource, ApplyOpts) (Resource, error)
 }
 
 type ExistsOpts struct {
 	SameUID bool
 }
 
+type ApplyOpts struct {
+	FieldManager string
+	Force        bool
+}
+
 type ResourcesImpl struct {
 	resourceTypes      ResourceTypes
 	coreClient         kubernetes.Interface
@@ -68,6 +76,9 @@ type ResourcesImpl struct {
 type ResourcesImplOpts struct {
 	FallbackAllowedNamespaces        []string
 	ScopeToFallbackAllowedNamespaces bool
+
+	// DryRun makes all mutating requests server-side dry runs
+	DryRun bool
 }
 
 func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interface,
@@ -84,6 +95,29 @@ func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interfa
 	}
 }
 
+// WithDryRun returns resources that send all mutating requests
+// as server-side dry runs (nothing is persisted by the API server)
+func (c *ResourcesImpl) WithDryRun() Resources {
+	opts := c.opts
+	opts.DryRun = true
+
+	return &ResourcesImpl{
+		resourceTypes:      c.resourceTypes,
+		coreClient:         c.coreClient,
+		dynamicClient:      c.dynamicClient,
+		mutedDynamicClient: c.mutedDynamicClient,
+		opts:               opts,
+		logger:             c.logger,
+	}
+}
+
+func (c *ResourcesImpl) dryRunOpt() []string {
+	if c.opts.DryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
 type unstructItems struct {
 	ResType ResourceType
 	Items   []unstructured.Unstructured
@@ -117,6 +151,26 @@ func (c *ResourcesImpl) All(resTypes []ResourceType, opts AllOpts) ([]Resource,
 
 			client := c.mutedDynamicClient.Resource(resType.GroupVersionResource)
 
+			if len(opts.Namespace) > 0 && resType.Namespaced() {
+				err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
+					list, err = client.Namespace(opts.Namespace).List(context.TODO(), *opts.ListOpts)
+					return err
+				})
+				if err != nil {
+					switch {
+					case errors.IsForbidden(err):
+						c.logger.Debug("Skipping forbidden group version: %#v", resType.GroupVersionResource)
+					case c.resourceTypes.CanIgnoreFailingGroupVersion(resType.GroupVersion()):
+						c.logger.Info("Ignoring group version: %#v: %s", resType.GroupVersionResource, err)
+					default:
+						fatalErrsCh <- fmt.Errorf("Listing %#v in namespace '%s': %w", resType.GroupVersionResource, opts.Namespace, err)
+					}
+					return
+				}
+				unstructItemsCh <- unstructItems{resType, list.Items}
+				return
+			}
+
 			// If resource is cluster scoped or request is not scoped to fallback
 			// allowed namespaces manually, then scope list to all namespaces
 			if !c.opts.ScopeToFallbackAllowedNamespaces || !resType.Namespaced() {
@@ -258,7 +312,7 @@ func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 	var createdUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{})
+		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{DryRun: c.dryRunOpt()})
 		return err
 	})
 	if err != nil {
@@ -285,7 +339,7 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 	var updatedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{})
+		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{DryRun: c.dryRunOpt()})
 		return err
 	})
 	if err != nil {
@@ -309,7 +363,7 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 	var patchedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{})
+		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{DryRun: c.dryRunOpt()})
 		return err
 	})
 	if err != nil {
@@ -319,6 +373,48 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 	return NewResourceUnstructured(*patchedUn, resType), nil
 }
 
+// Apply uses server-side apply to save resource.
+// Server managed metadata fields are not sent since they are not applicable.
+func (c *ResourcesImpl) Apply(resource Resource, opts ApplyOpts) (Resource, error) {
+	if resourcesDebug {
+		t1 := time.Now().UTC()
+		defer func() { c.logger.Debug("apply %s", time.Now().UTC().Sub(t1)) }()
+
+		bs, _ := resource.AsYAMLBytes()
+		c.logger.Debug("apply resource %s\n%s\n", resource.Description(), bs)
+	}
+
+	resClient, resType, err := c.resourceClient(resource, resourceClientOpts{Warnings: true})
+	if err != nil {
+		return nil, err
+	}
+
+	appliedUn := resource.unstructuredPtr().DeepCopy()
+
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(appliedUn.Object, "metadata", field)
+	}
+
+	data, err := json.Marshal(appliedUn.Object)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling resource %s for apply: %w", resource.Description(), err)
+	}
+
+	patchOpts := metav1.PatchOptions{FieldManager: opts.FieldManager, Force: &opts.Force, DryRun: c.dryRunOpt()}
+
+	var appliedResUn *unstructured.Unstructured
+
+	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
+		appliedResUn, err = resClient.Patch(context.TODO(), resource.Name(), types.ApplyPatchType, data, patchOpts)
+		return err
+	})
+	if err != nil {
+		return nil, c.resourceErr(err, "Applying", resource)
+	}
+
+	return NewResourceUnstructured(*appliedResUn, resType), nil
+}
+
 func (c *ResourcesImpl) Delete(resource Resource) error {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
@@ -339,7 +435,7 @@ func (c *ResourcesImpl) Delete(resource Resource) error {
 		// TODO is setting deletion policy a correct thing to do?
 		// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#setting-the-cascading-deletion-policy
 		delPol := metav1.DeletePropagationBackground
-		delOpts := metav1.DeleteOptions{PropagationPolicy: &delPol}
+		delOpts := metav1.DeleteOptions{PropagationPolicy: &delPol, DryRun: c.dryRunOpt()}
 
 		// Some resources may not have UID (example: PodMetrics.metrics.k8s.io)
 		resUID := types.UID(resource.UID())
@@ -365,6 +461,37 @@ func (c *ResourcesImpl) Delete(resource Resource) error {
 	return nil
 }
 
+// Watch starts watching resources of given type in given namespace
+// (empty namespace means all namespaces for namespaced types).
+// Unless resource version is specified, watch starts from resource version
+// of the current list so that already existing resources are not sent as added.
+func (c *ResourcesImpl) Watch(resType ResourceType, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	var resClient dynamic.ResourceInterface = c.mutedDynamicClient.Resource(resType.GroupVersionResource)
+	if resType.Namespaced() && len(namespace) > 0 {
+		resClient = c.mutedDynamicClient.Resource(resType.GroupVersionResource).Namespace(namespace)
+	}
+
+	if len(opts.ResourceVersion) == 0 {
+		listOpts := opts
+		listOpts.Limit = 1 // only resource version is needed
+
+		list, err := resClient.List(context.TODO(), listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("Listing %s in namespace '%s': %w", resType.GroupVersionResource.String(), namespace, err)
+		}
+		opts.ResourceVersion = list.GetResourceVersion()
+	}
+
+	opts.AllowWatchBookmarks = true
+
+	watcher, err := resClient.Watch(context.TODO(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("Watching %s in namespace '%s': %w", resType.GroupVersionResource.String(), namespace, err)
+	}
+
+	return watcher, nil
+}
+
 func (c *ResourcesImpl) Get(resource Resource) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
@@ -616,6 +743,8 @@ func uniqAndValidNamespaces(in []string) []string {
 type AllOpts struct {
 	ListOpts           *metav1.ListOptions
 	ResourceNamespaces []string
+	// Namespace (if specified) scopes listing of namespaced resources
+	Namespace string
 }
 
 type resourceStatusErr struct {
@@ -8,19 +8,29 @@ import (
 
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Set by DaemonSet controller on Pods it creates
+	appsV1DaemonSetTemplateGenerationLabelKey = "pod-template-generation"
+	// Set by DaemonSet controller on DaemonSet; template generation
+	// only changes with pod template unlike metadata.generation
+	appsV1DaemonSetTemplateGenerationAnnKey = "deprecated.daemonset.template.generation"
 )
 
 type AppsV1DaemonSet struct {
-	resource ctlres.Resource
+	resource     ctlres.Resource
+	associatedRs []ctlres.Resource
 }
 
-func NewAppsV1DaemonSet(resource ctlres.Resource) *AppsV1DaemonSet {
+func NewAppsV1DaemonSet(resource ctlres.Resource, associatedRs []ctlres.Resource) *AppsV1DaemonSet {
 	matcher := ctlres.APIVersionKindMatcher{
 		APIVersion: "apps/v1",
 		Kind:       "DaemonSet",
 	}
 	if matcher.Matches(resource) {
-		return &AppsV1DaemonSet{resource}
+		return &AppsV1DaemonSet{resource, associatedRs}
 	}
 	return nil
 }
@@ -38,6 +48,22 @@ func (s AppsV1DaemonSet) IsDoneApplying() DoneApplyState {
 			"Waiting for generation %d to be observed", dset.Generation)}
 	}
 
+	state := s.replicasState(dset)
+	if !state.Done {
+		// Detect stuck rollout instead of waiting until timeout
+		failedState, err := newPodFailures(s.resource, s.associatedRs, s.isCurrentPod(dset)).FailedState()
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: %s", err)}
+		}
+		if failedState != nil {
+			return *failedState
+		}
+	}
+
+	return state
+}
+
+func (s AppsV1DaemonSet) replicasState(dset appsv1.DaemonSet) DoneApplyState {
 	// ensure updated pods are actually scheduled before checking number unavailable to avoid
 	// race condition between pod scheduler and kapp state check
 	notReady := dset.Status.DesiredNumberScheduled - dset.Status.UpdatedNumberScheduled
@@ -53,3 +79,19 @@ func (s AppsV1DaemonSet) IsDoneApplying() DoneApplyState {
 
 	return DoneApplyState{Done: true, Successful: true}
 }
+
+// isCurrentPod selects Pods of the latest template generation, so that
+// failures of Pods that are about to be replaced are not considered
+func (AppsV1DaemonSet) isCurrentPod(dset appsv1.DaemonSet) func(corev1.Pod) bool {
+	return func(pod corev1.Pod) bool {
+		templateGen, found := dset.Annotations[appsV1DaemonSetTemplateGenerationAnnKey]
+		if !found {
+			return true
+		}
+		podGen, found := pod.Labels[appsV1DaemonSetTemplateGenerationLabelKey]
+		if !found {
+			return true
+		}
+		return podGen == templateGen
+	}
+}
@@ -4,8 +4,10 @@
 package resourcesmisc_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
@@ -140,9 +142,85 @@ status:
 	require.Equal(t, expectedState, state)
 }
 
+func TestAppsV1DaemonSetPodFailures(t *testing.T) {
+	currentData := `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: fluentd
+  # Generation also changes with non-template changes (e.g. update strategy)
+  generation: 3
+  annotations:
+    deprecated.daemonset.template.generation: "2"
+status:
+  desiredNumberScheduled: 1
+  numberUnavailable: 1
+  observedGeneration: 3
+  updatedNumberScheduled: 1
+`
+
+	podTmpl := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  creationTimestamp: "%s"
+  labels:
+    pod-template-generation: "%s"
+status:
+  phase: Pending
+  conditions:
+  - type: PodScheduled
+    status: "False"
+    reason: Unschedulable
+    message: 0/1 nodes are available
+    lastTransitionTime: "%[2]s"
+`
+	failedAt := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+
+	oldPods, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(
+		fmt.Sprintf(podTmpl, "fluentd-old", failedAt, "1")))).Resources()
+	require.NoErrorf(t, err, "Expected pods to parse")
+
+	oldState := ctlresm.NewAppsV1DaemonSet(ctlres.MustNewResourceFromBytes([]byte(currentData)), oldPods).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for 1 unavailable pods"}, oldState)
+
+	podData := fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: fluentd-abcde
+  creationTimestamp: "%s"
+  labels:
+    pod-template-generation: "2"
+status:
+  phase: Pending
+  conditions:
+  - type: PodScheduled
+    status: "False"
+    reason: Unschedulable
+    message: 0/1 nodes are available
+    lastTransitionTime: "%[1]s"
+`, time.Now().Add(-10*time.Minute).UTC().Format(time.RFC3339))
+
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(currentData))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	pods, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(podData))).Resources()
+	require.NoErrorf(t, err, "Expected pods to parse")
+
+	state := ctlresm.NewAppsV1DaemonSet(newResources[0], pods).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: false,
+		Message:    "Pod 'fluentd-abcde' is failing: Unschedulable (message: 0/1 nodes are available)",
+	}
+	require.Equal(t, expectedState, state)
+}
+
 func buildDaemonSet(resourcesBs string, t *testing.T) *ctlresm.AppsV1DaemonSet {
 	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
 	require.NoErrorf(t, err, "Expected resources to parse")
 
-	return ctlresm.NewAppsV1DaemonSet(newResources[0])
+	return ctlresm.NewAppsV1DaemonSet(newResources[0], nil)
 }
@@ -5,21 +5,24 @@ package resourcesmisc
 
 import (
 	"fmt"
+
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type AppsV1StatefulSet struct {
-	resource ctlres.Resource
+	resource     ctlres.Resource
+	associatedRs []ctlres.Resource
 }
 
-func NewAppsV1StatefulSet(resource ctlres.Resource, _ []ctlres.Resource) *AppsV1StatefulSet {
+func NewAppsV1StatefulSet(resource ctlres.Resource, associatedRs []ctlres.Resource) *AppsV1StatefulSet {
 	matcher := ctlres.APIVersionKindMatcher{
 		APIVersion: "apps/v1",
 		Kind:       "StatefulSet",
 	}
 	if matcher.Matches(resource) {
-		return &AppsV1StatefulSet{resource}
+		return &AppsV1StatefulSet{resource, associatedRs}
 	}
 	return nil
 }
@@ -37,6 +40,22 @@ func (s AppsV1StatefulSet) IsDoneApplying() DoneApplyState {
 			"Waiting for generation %d to be observed", statefulSet.Generation)}
 	}
 
+	state := s.replicasState(statefulSet)
+	if !state.Done {
+		// Detect stuck rollout instead of waiting until timeout
+		failedState, err := newPodFailures(s.resource, s.associatedRs, s.isCurrentPod(statefulSet)).FailedState()
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: %s", err)}
+		}
+		if failedState != nil {
+			return *failedState
+		}
+	}
+
+	return state
+}
+
+func (s AppsV1StatefulSet) replicasState(statefulSet appsv1.StatefulSet) DoneApplyState {
 	if statefulSet.Spec.Replicas == nil {
 		return DoneApplyState{Done: true, Successful: false,
 			Message: fmt.Sprintf("Error: Failed to find spec.replicas")}
@@ -74,6 +93,17 @@ func (s AppsV1StatefulSet) IsDoneApplying() DoneApplyState {
 	return DoneApplyState{Done: true, Successful: true}
 }
 
+// isCurrentPod selects Pods of the latest revision, so that
+// failures of Pods that are about to be replaced are not considered
+func (AppsV1StatefulSet) isCurrentPod(statefulSet appsv1.StatefulSet) func(corev1.Pod) bool {
+	return func(pod corev1.Pod) bool {
+		if len(statefulSet.Status.UpdateRevision) == 0 {
+			return true
+		}
+		return pod.Labels[appsv1.StatefulSetRevisionLabel] == statefulSet.Status.UpdateRevision
+	}
+}
+
 func (AppsV1StatefulSet) partition(statefulSet appsv1.StatefulSet) bool {
 	return statefulSet.Spec.UpdateStrategy.RollingUpdate != nil &&
 		statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
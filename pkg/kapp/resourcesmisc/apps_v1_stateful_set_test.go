@@ -4,8 +4,10 @@
 package resourcesmisc_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
@@ -291,6 +293,75 @@ status:
 	require.Equal(t, expectedState, state, "Found incorrect state")
 }
 
+func TestAppsV1StatefulSetPodFailures(t *testing.T) {
+	stsData := `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+  generation: 1
+  annotations:
+    kapp.k14s.io/pod-failure-grace-period: 1m
+spec:
+  replicas: 1
+status:
+  observedGeneration: 1
+  updateRevision: web-2
+`
+
+	podTpl := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-0
+  creationTimestamp: "%s"
+  labels:
+    controller-revision-hash: %s
+status:
+  phase: Running
+  containerStatuses:
+  - name: web
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+        message: back-off 5m0s restarting failed container
+`
+
+	oldCreationTime := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+	newCreationTime := time.Now().UTC().Format(time.RFC3339)
+
+	state := buildStatefulSetWithPods(stsData, fmt.Sprintf(podTpl, oldCreationTime, "web-2"), t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: false,
+		Message:    "Pod 'web-0' is failing: CrashLoopBackOff (container: web) (message: back-off 5m0s restarting failed container)",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+
+	// Pod is still within grace period
+	state = buildStatefulSetWithPods(stsData, fmt.Sprintf(podTpl, newCreationTime, "web-2"), t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for 1 replicas to be updated",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+
+	// Pod belongs to previous revision and is about to be replaced
+	state = buildStatefulSetWithPods(stsData, fmt.Sprintf(podTpl, oldCreationTime, "web-1"), t).IsDoneApplying()
+	require.Equal(t, expectedState, state, "Found incorrect state")
+}
+
+func buildStatefulSetWithPods(resourcesBs, podsBs string, t *testing.T) *ctlresm.AppsV1StatefulSet {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	pods, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(podsBs))).Resources()
+	require.NoErrorf(t, err, "Expected pods to parse")
+
+	return ctlresm.NewAppsV1StatefulSet(newResources[0], pods)
+}
+
 func buildStatefulSet(resourcesBs string, t *testing.T) *ctlresm.AppsV1StatefulSet {
 	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
 	require.NoErrorf(t, err, "Expected resources to parse")
@@ -0,0 +1,71 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	autoscalingV2HPADisableWaitAnnKey = "kapp.k14s.io/disable-horizontal-pod-autoscaler-wait" // valid value is ''
+)
+
+type AutoscalingV2HPA struct {
+	resource ctlres.Resource
+}
+
+func NewAutoscalingV2HPA(resource ctlres.Resource) *AutoscalingV2HPA {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "autoscaling/v2",
+		Kind:       "HorizontalPodAutoscaler",
+	}
+	if matcher.Matches(resource) {
+		if _, found := resource.Annotations()[autoscalingV2HPADisableWaitAnnKey]; !found {
+			return &AutoscalingV2HPA{resource}
+		}
+	}
+	return nil
+}
+
+func (s AutoscalingV2HPA) IsDoneApplying() DoneApplyState {
+	hpa := autoscalingv2.HorizontalPodAutoscaler{}
+
+	err := s.resource.AsTypedObj(&hpa)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	if hpa.Status.ObservedGeneration == nil || hpa.Generation != *hpa.Status.ObservedGeneration {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for generation %d to be observed", hpa.Generation)}
+	}
+
+	for _, cond := range hpa.Status.Conditions {
+		switch cond.Type {
+		case autoscalingv2.AbleToScale:
+			if cond.Status == corev1.ConditionFalse {
+				return DoneApplyState{Done: false, Message: fmt.Sprintf(
+					"Autoscaler is not able to scale: %s (message: %s)", cond.Reason, cond.Message)}
+			}
+
+		case autoscalingv2.ScalingActive:
+			switch {
+			case cond.Status == corev1.ConditionTrue:
+				return DoneApplyState{Done: true, Successful: true}
+			// Scaling is disabled when target is scaled to zero replicas
+			case cond.Reason == "ScalingDisabled":
+				return DoneApplyState{Done: true, Successful: true, Message: "Scaling is disabled"}
+			default:
+				return DoneApplyState{Done: false, Message: fmt.Sprintf(
+					"Autoscaler is not active: %s (message: %s)", cond.Reason, cond.Message)}
+			}
+		}
+	}
+
+	return DoneApplyState{Done: false, Message: "Waiting for ScalingActive condition"}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestAutoscalingV2HPA(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "generation not observed, waiting",
+			data: `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+  generation: 1
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for generation 1 to be observed"},
+		},
+		{
+			name: "metrics unavailable, waiting",
+			data: `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+  generation: 1
+status:
+  observedGeneration: 1
+  conditions:
+  - type: AbleToScale
+    status: "True"
+  - type: ScalingActive
+    status: "False"
+    reason: FailedGetResourceMetric
+    message: missing request for cpu
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Autoscaler is not active: FailedGetResourceMetric (message: missing request for cpu)"},
+		},
+		{
+			name: "scaling disabled, done",
+			data: `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+  generation: 1
+status:
+  observedGeneration: 1
+  conditions:
+  - type: ScalingActive
+    status: "False"
+    reason: ScalingDisabled
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Scaling is disabled"},
+		},
+		{
+			name: "scaling active, done",
+			data: `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+  generation: 1
+status:
+  observedGeneration: 1
+  conditions:
+  - type: AbleToScale
+    status: "True"
+  - type: ScalingActive
+    status: "True"
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedState, buildHPA(tc.data, t).IsDoneApplying())
+		})
+	}
+}
+
+func buildHPA(resourcesBs string, t *testing.T) *ctlresm.AutoscalingV2HPA {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewAutoscalingV2HPA(newResources[0])
+}
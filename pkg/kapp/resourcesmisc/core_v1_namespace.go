@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	coreV1NamespaceDisableWaitAnnKey = "kapp.k14s.io/disable-namespace-wait" // valid value is ''
+)
+
+type CoreV1Namespace struct {
+	resource ctlres.Resource
+}
+
+func NewCoreV1Namespace(resource ctlres.Resource) *CoreV1Namespace {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+	}
+	if matcher.Matches(resource) {
+		if _, found := resource.Annotations()[coreV1NamespaceDisableWaitAnnKey]; !found {
+			return &CoreV1Namespace{resource}
+		}
+	}
+	return nil
+}
+
+func (s CoreV1Namespace) IsDoneApplying() DoneApplyState {
+	ns := corev1.Namespace{}
+
+	err := s.resource.AsTypedObj(&ns)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	if !s.resource.IsDeleting() && ns.Status.Phase != corev1.NamespaceTerminating {
+		return DoneApplyState{Done: true, Successful: true}
+	}
+
+	// Namespace finalization is described via conditions
+	// (e.g. remaining content, failed content deletion)
+	var msgs []string
+
+	for _, cond := range ns.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && len(cond.Message) > 0 {
+			msgs = append(msgs, cond.Message)
+		}
+	}
+
+	if len(msgs) > 0 {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf("Namespace is terminating: %s", strings.Join(msgs, "; "))}
+	}
+
+	return DoneApplyState{Done: false, Message: "Namespace is terminating"}
+}
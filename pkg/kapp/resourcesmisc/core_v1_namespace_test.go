@@ -0,0 +1,65 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestCoreV1Namespace(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "active namespace, done",
+			data: `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+status:
+  phase: Active
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+		{
+			name: "terminating namespace with remaining content, waiting",
+			data: `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+  deletionTimestamp: "2024-01-01T00:00:00Z"
+status:
+  phase: Terminating
+  conditions:
+  - type: NamespaceDeletionContentFailure
+    status: "False"
+    message: All content successfully deleted
+  - type: NamespaceContentRemaining
+    status: "True"
+    message: 'Some resources are remaining: pods. has 2 resource instances'
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false,
+				Message: "Namespace is terminating: Some resources are remaining: pods. has 2 resource instances"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedState, buildNamespace(tc.data, t).IsDoneApplying())
+		})
+	}
+}
+
+func buildNamespace(resourcesBs string, t *testing.T) *ctlresm.CoreV1Namespace {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewCoreV1Namespace(newResources[0])
+}
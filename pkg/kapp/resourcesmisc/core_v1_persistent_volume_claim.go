@@ -0,0 +1,95 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+const (
+	coreV1PVCDisableWaitAnnKey = "kapp.k14s.io/disable-persistent-volume-claim-wait" // valid value is ''
+
+	coreV1PVCSelectedNodeAnnKey = "volume.kubernetes.io/selected-node"
+)
+
+type CoreV1PVC struct {
+	resource              ctlres.Resource
+	volumeBindingModeFunc func(string) (storagev1.VolumeBindingMode, bool, error)
+}
+
+// NewCoreV1PVC returns PVC waiter. volumeBindingModeFunc finds volume binding mode
+// of storage class by name (false if it does not exist); claims are expected
+// to be bound if volumeBindingModeFunc is not provided.
+func NewCoreV1PVC(resource ctlres.Resource, volumeBindingModeFunc func(string) (storagev1.VolumeBindingMode, bool, error)) *CoreV1PVC {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+	}
+	if matcher.Matches(resource) {
+		if _, found := resource.Annotations()[coreV1PVCDisableWaitAnnKey]; !found {
+			return &CoreV1PVC{resource, volumeBindingModeFunc}
+		}
+	}
+	return nil
+}
+
+func (s CoreV1PVC) IsDoneApplying() DoneApplyState {
+	pvc := corev1.PersistentVolumeClaim{}
+
+	err := s.resource.AsTypedObj(&pvc)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimBound:
+		return DoneApplyState{Done: true, Successful: true}
+
+	case corev1.ClaimLost:
+		return DoneApplyState{Done: true, Successful: false, Message: "Claim lost its underlying volume"}
+	}
+
+	// Volume is being bound to a specific volume or provisioned for a scheduled consumer
+	if len(pvc.Spec.VolumeName) > 0 || s.hasAnnotation(coreV1PVCSelectedNodeAnnKey) {
+		return DoneApplyState{Done: false, Message: "Waiting for claim to be bound"}
+	}
+
+	bindingMode, err := s.volumeBindingMode(pvc)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Finding storage class: %s", err)}
+	}
+
+	// Storage classes with WaitForFirstConsumer binding mode do not
+	// start provisioning until a Pod using the claim is scheduled,
+	// hence claim may stay pending until its consumer is deployed
+	if bindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		return DoneApplyState{Done: true, Successful: true, Message: "Claim is pending (waiting for first consumer)"}
+	}
+
+	return DoneApplyState{Done: false, Message: "Waiting for claim to be bound"}
+}
+
+func (s CoreV1PVC) volumeBindingMode(pvc corev1.PersistentVolumeClaim) (storagev1.VolumeBindingMode, error) {
+	if pvc.Spec.StorageClassName == nil || len(*pvc.Spec.StorageClassName) == 0 || s.volumeBindingModeFunc == nil {
+		return storagev1.VolumeBindingImmediate, nil
+	}
+
+	mode, found, err := s.volumeBindingModeFunc(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return storagev1.VolumeBindingImmediate, nil
+	}
+	return mode, nil
+}
+
+func (s CoreV1PVC) hasAnnotation(key string) bool {
+	_, found := s.resource.Annotations()[key]
+	return found
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+func TestCoreV1PVC(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		storageClass  string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "bound claim, done",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+status:
+  phase: Bound
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+		{
+			name: "lost claim, failed",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+status:
+  phase: Lost
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: false, Message: "Claim lost its underlying volume"},
+		},
+		{
+			name: "pending claim being provisioned, waiting",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    volume.kubernetes.io/storage-provisioner: rancher.io/local-path
+status:
+  phase: Pending
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for claim to be bound"},
+		},
+		{
+			name: "pending claim with storage class waiting for first consumer, done",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  storageClassName: local
+status:
+  phase: Pending
+`,
+			storageClass: `
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: local
+provisioner: rancher.io/local-path
+volumeBindingMode: WaitForFirstConsumer
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Claim is pending (waiting for first consumer)"},
+		},
+		{
+			name: "pending claim with storage class binding immediately, waiting",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  storageClassName: local
+status:
+  phase: Pending
+`,
+			storageClass: `
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: local
+provisioner: rancher.io/local-path
+volumeBindingMode: Immediate
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for claim to be bound"},
+		},
+		{
+			name: "pending claim with missing storage class, waiting",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  storageClassName: missing
+status:
+  phase: Pending
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for claim to be bound"},
+		},
+		{
+			name: "pending claim waiting for first consumer scheduled on a node, waiting",
+			data: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    volume.kubernetes.io/selected-node: node1
+spec:
+  storageClassName: local
+status:
+  phase: Pending
+`,
+			storageClass: `
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: local
+provisioner: rancher.io/local-path
+volumeBindingMode: WaitForFirstConsumer
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for claim to be bound"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedState, buildPVC(tc.data, tc.storageClass, t).IsDoneApplying())
+		})
+	}
+}
+
+func TestCoreV1PVCDisableWait(t *testing.T) {
+	data := `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    kapp.k14s.io/disable-persistent-volume-claim-wait: ""
+`
+	require.Nil(t, buildPVC(data, "", t))
+}
+
+func buildPVC(resourcesBs, storageClassBs string, t *testing.T) *ctlresm.CoreV1PVC {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	storageClasses, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(storageClassBs))).Resources()
+	require.NoErrorf(t, err, "Expected storage classes to parse")
+
+	volumeBindingModeFunc := func(name string) (storagev1.VolumeBindingMode, bool, error) {
+		for _, res := range storageClasses {
+			if res.Name() == name {
+				sc := storagev1.StorageClass{}
+				err := res.AsTypedObj(&sc)
+				if err != nil {
+					return "", false, err
+				}
+				return *sc.VolumeBindingMode, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	return ctlresm.NewCoreV1PVC(newResources[0], volumeBindingModeFunc)
+}
@@ -11,15 +11,84 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// CustomWaitRules holds wait rules along with their
+// CEL expressions compiled once for all resources
+type CustomWaitRules struct {
+	rules       []ctlconf.WaitRule
+	celPrograms []*ctlconf.WaitRuleCELPrograms
+	celErrs     []error
+}
+
+func NewCustomWaitRules(rules []ctlconf.WaitRule) CustomWaitRules {
+	result := CustomWaitRules{
+		rules:       rules,
+		celPrograms: make([]*ctlconf.WaitRuleCELPrograms, len(rules)),
+		celErrs:     make([]error, len(rules)),
+	}
+
+	for i, rule := range rules {
+		if rule.CEL != nil {
+			programs, err := rule.CEL.Programs()
+			result.celPrograms[i] = &programs
+			result.celErrs[i] = err
+		}
+	}
+
+	return result
+}
+
+// AssociatedResourcesQueries returns queries for associated resources
+// declared by the wait rule that matches given resource
+func (r CustomWaitRules) AssociatedResourcesQueries(resource ctlres.Resource) ([]ctlres.AssociatedResourcesQuery, error) {
+	for _, rule := range r.rules {
+		if rule.OnlyConfiguresTimeout() {
+			continue
+		}
+		if rule.ResourceMatcher().Matches(resource) {
+			var queries []ctlres.AssociatedResourcesQuery
+			for _, assocRes := range rule.AssociatedResources {
+				query, err := assocRes.AsQuery(resource)
+				if err != nil {
+					return nil, fmt.Errorf("Building associated resources query: %w", err)
+				}
+				queries = append(queries, query)
+			}
+			return queries, nil
+		}
+	}
+	return nil, nil
+}
+
+// Timeout returns timeout and timeout action specified by the first
+// wait rule that matches given resource and specifies either of them
+// (both are taken from the same rule so that they are not mixed up)
+func (r CustomWaitRules) Timeout(resource ctlres.Resource) (string, string) {
+	for _, rule := range r.rules {
+		if len(rule.Timeout) == 0 && len(rule.TimeoutAction) == 0 {
+			continue
+		}
+		if rule.ResourceMatcher().Matches(resource) {
+			return rule.Timeout, rule.TimeoutAction
+		}
+	}
+	return "", ""
+}
+
 type CustomWaitingResource struct {
-	resource ctlres.Resource
-	waitRule ctlconf.WaitRule
+	resource     ctlres.Resource
+	associatedRs []ctlres.Resource
+	waitRule     ctlconf.WaitRule
+	celPrograms  *ctlconf.WaitRuleCELPrograms
+	celErr       error
 }
 
-func NewCustomWaitingResource(resource ctlres.Resource, waitRules []ctlconf.WaitRule) *CustomWaitingResource {
-	for _, rule := range waitRules {
+func NewCustomWaitingResource(resource ctlres.Resource, associatedRs []ctlres.Resource, waitRules CustomWaitRules) *CustomWaitingResource {
+	for i, rule := range waitRules.rules {
+		if rule.OnlyConfiguresTimeout() {
+			continue
+		}
 		if rule.ResourceMatcher().Matches(resource) {
-			return &CustomWaitingResource{resource, rule}
+			return &CustomWaitingResource{resource, associatedRs, rule, waitRules.celPrograms[i], waitRules.celErrs[i]}
 		}
 	}
 	return nil
@@ -55,11 +124,22 @@ func (s CustomWaitingResource) IsDoneApplying() DoneApplyState {
 			"Waiting for generation %d to be observed", obj.Metadata.Generation)}
 	}
 
+	if s.waitRule.CEL != nil {
+		if s.celErr != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+				"Error: Compiling cel wait rule: %s", s.celErr)}
+		}
+		result := WaitRuleCEL{Programs: *s.celPrograms}.Apply(s.resource, s.associatedRs)
+		return DoneApplyState{Done: result.Done, Successful: result.Successful, Message: result.Message}
+	}
+
 	if s.waitRule.Ytt != nil {
 		configObj, err := WaitRuleContractV1{
 			ResourceMatcher: ctlres.AnyMatcher{
 				Matchers: ctlconf.ResourceMatchers(s.waitRule.ResourceMatchers).AsResourceMatchers()},
-			Starlark: s.waitRule.Ytt.FuncContractV1.Resource,
+			Starlark:            s.waitRule.Ytt.FuncContractV1.Resource,
+			IncludeAssociatedRs: len(s.waitRule.AssociatedResources) > 0,
+			AssociatedRs:        s.associatedRs,
 		}.Apply(s.resource)
 		if err != nil {
 			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
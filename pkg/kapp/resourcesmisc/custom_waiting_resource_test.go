@@ -0,0 +1,208 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCustomWaitingResourceCEL(t *testing.T) {
+	celRule := &ctlconf.WaitRuleCEL{
+		Success:    `resource.status.phase == "Ready"`,
+		Failure:    `resource.status.phase == "Failed"`,
+		InProgress: `size(associatedResources) > 0`,
+	}
+
+	for _, tc := range []struct {
+		name          string
+		data          string
+		associatedRs  string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "success expression matches",
+			data: `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+status:
+  phase: Ready
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true,
+				Message: "Encountered success (cel success expression is true)"},
+		},
+		{
+			name: "failure expression matches",
+			data: `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+status:
+  phase: Failed
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: false,
+				Message: "Encountered failure (cel failure expression is true)"},
+		},
+		{
+			name: "in progress expression matches associated resources",
+			data: `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+status:
+  phase: Pending
+`,
+			associatedRs: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo-1
+`,
+			expectedState: ctlresm.DoneApplyState{Message: "In progress (cel inProgress expression is true)"},
+		},
+		{
+			name: "status is missing",
+			data: `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+`,
+			expectedState: ctlresm.DoneApplyState{Message: "No failing or successful cel expression matched " +
+				"(last error: success expression: no such key: status)"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := buildCustomWaitingResource(tc.data, tc.associatedRs, []ctlconf.WaitRule{fooWaitRule(celRule)}, t)
+			require.Equal(t, tc.expectedState, res.IsDoneApplying())
+		})
+	}
+}
+
+func TestCustomWaitingResourceCELCompileError(t *testing.T) {
+	rule := fooWaitRule(&ctlconf.WaitRuleCEL{Success: `resource.status.phase ==`})
+
+	res := buildCustomWaitingResource(`
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+`, "", []ctlconf.WaitRule{rule}, t)
+
+	state := res.IsDoneApplying()
+	require.True(t, state.Done)
+	require.False(t, state.Successful)
+	require.True(t, strings.HasPrefix(state.Message, "Error: Compiling cel wait rule: "), state.Message)
+}
+
+func fooWaitRule(celRule *ctlconf.WaitRuleCEL) ctlconf.WaitRule {
+	return ctlconf.WaitRule{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "example.com/v1", Kind: "Foo"},
+		}},
+		CEL: celRule,
+	}
+}
+
+func buildCustomWaitingResource(resourceBs, associatedRsBs string, rules []ctlconf.WaitRule, t *testing.T) *ctlresm.CustomWaitingResource {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourceBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	associatedRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(associatedRsBs))).Resources()
+	require.NoErrorf(t, err, "Expected associated resources to parse")
+
+	res := ctlresm.NewCustomWaitingResource(newResources[0], associatedRs, ctlresm.NewCustomWaitRules(rules))
+	require.NotNil(t, res, "Expected wait rule to match resource")
+
+	return res
+}
+
+func TestCustomWaitingResourceYttWithAssociatedResources(t *testing.T) {
+	rule := ctlconf.WaitRule{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "example.com/v1", Kind: "Foo"},
+		}},
+		AssociatedResources: []ctlconf.WaitRuleAssociatedResource{{
+			APIVersion: "apps/v1", Kind: "Deployment", NameTemplate: "$(name)-server",
+		}},
+		Ytt: &ctlconf.WaitRuleYtt{FuncContractV1: &ctlconf.FuncContractV1{Resource: `
+def is_done(resource, associated_resources):
+  for res in associated_resources:
+    if res["kind"] == "Deployment" and res["status"]["availableReplicas"] > 0:
+      return {"done": True, "successful": True, "message": "Deployment is available"}
+    end
+  end
+  return {"done": False, "message": "Waiting for deployment"}
+end
+`}},
+	}
+
+	resource := `
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+  namespace: app
+`
+
+	res := buildCustomWaitingResource(resource, "", []ctlconf.WaitRule{rule}, t)
+	require.Equal(t, ctlresm.DoneApplyState{Message: "Waiting for deployment"}, res.IsDoneApplying())
+
+	res = buildCustomWaitingResource(resource, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo-server
+  namespace: app
+status:
+  availableReplicas: 1
+`, []ctlconf.WaitRule{rule}, t)
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Deployment is available"}, res.IsDoneApplying())
+
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resource))).Resources()
+	require.NoError(t, err)
+
+	queries, err := ctlresm.NewCustomWaitRules([]ctlconf.WaitRule{rule}).AssociatedResourcesQueries(newResources[0])
+	require.NoError(t, err)
+	require.Equal(t, []ctlres.AssociatedResourcesQuery{{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo-server",
+	}}, queries)
+}
+
+func TestCustomWaitRulesTimeoutFromSameRule(t *testing.T) {
+	rules := []ctlconf.WaitRule{
+		fooWaitRule(nil),
+		{
+			ResourceMatchers: fooWaitRule(nil).ResourceMatchers,
+			TimeoutAction:    ctlconf.WaitRuleTimeoutActionWarn,
+		},
+		{
+			ResourceMatchers: fooWaitRule(nil).ResourceMatchers,
+			Timeout:          "5m",
+		},
+	}
+
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(`
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+`))).Resources()
+	require.NoError(t, err)
+
+	timeout, timeoutAction := ctlresm.NewCustomWaitRules(rules).Timeout(newResources[0])
+	require.Equal(t, "", timeout)
+	require.Equal(t, ctlconf.WaitRuleTimeoutActionWarn, timeoutAction)
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	// Waiting for address is opt-in since not all clusters run
+	// an ingress controller that populates ingress status
+	networkingV1IngressWaitAnnKey = "kapp.k14s.io/wait-for-ingress-address" // valid value is ''
+)
+
+type NetworkingV1Ingress struct {
+	resource ctlres.Resource
+}
+
+func NewNetworkingV1Ingress(resource ctlres.Resource) *NetworkingV1Ingress {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+	}
+	if matcher.Matches(resource) {
+		if _, found := resource.Annotations()[networkingV1IngressWaitAnnKey]; found {
+			return &NetworkingV1Ingress{resource}
+		}
+	}
+	return nil
+}
+
+func (s NetworkingV1Ingress) IsDoneApplying() DoneApplyState {
+	ing := networkingv1.Ingress{}
+
+	err := s.resource.AsTypedObj(&ing)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	if len(ing.Status.LoadBalancer.Ingress) == 0 {
+		return DoneApplyState{Done: false, Message: "Waiting for load balancer address to be assigned"}
+	}
+
+	return DoneApplyState{Done: true, Successful: true}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestNetworkingV1Ingress(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "ingress without address, waiting",
+			data: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  annotations:
+    kapp.k14s.io/wait-for-ingress-address: ""
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for load balancer address to be assigned"},
+		},
+		{
+			name: "ingress with address, done",
+			data: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  annotations:
+    kapp.k14s.io/wait-for-ingress-address: ""
+status:
+  loadBalancer:
+    ingress:
+    - ip: 10.0.0.1
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := buildIngress(tc.data, t)
+			require.NotNil(t, ing)
+			require.Equal(t, tc.expectedState, ing.IsDoneApplying())
+		})
+	}
+}
+
+func TestNetworkingV1IngressWithoutOptIn(t *testing.T) {
+	data := `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+`
+	require.Nil(t, buildIngress(data, t))
+}
+
+func buildIngress(resourcesBs string, t *testing.T) *ctlresm.NetworkingV1Ingress {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewNetworkingV1Ingress(newResources[0])
+}
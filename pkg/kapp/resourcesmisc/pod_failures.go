@@ -0,0 +1,122 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+	"time"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	podFailureGracePeriodAnnKey  = "kapp.k14s.io/pod-failure-grace-period" // valid value is a duration (e.g. 5m)
+	podFailureGracePeriodDefault = 5 * time.Minute
+)
+
+var (
+	// Container waiting reasons that are unlikely to resolve on their own
+	podFailureWaitingReasons = map[string]struct{}{
+		"CrashLoopBackOff":           {},
+		"ImagePullBackOff":           {},
+		"ErrImagePull":               {},
+		"InvalidImageName":           {},
+		"CreateContainerConfigError": {},
+	}
+)
+
+// podFailures detects associated Pods that are stuck (e.g. crash looping,
+// unable to pull image or be scheduled) for longer than a grace period
+// configured via annotation on the controlling resource.
+type podFailures struct {
+	resource     ctlres.Resource
+	associatedRs []ctlres.Resource
+	isCurrentPod func(corev1.Pod) bool
+	now          func() time.Time
+}
+
+func newPodFailures(resource ctlres.Resource, associatedRs []ctlres.Resource, isCurrentPod func(corev1.Pod) bool) podFailures {
+	return podFailures{resource, associatedRs, isCurrentPod, time.Now}
+}
+
+// FailedState returns failed state if any associated Pod is stuck
+func (f podFailures) FailedState() (*DoneApplyState, error) {
+	gracePeriod := podFailureGracePeriodDefault
+
+	if val, found := f.resource.Annotations()[podFailureGracePeriodAnnKey]; found {
+		var err error
+		gracePeriod, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("Expected annotation '%s' to be a duration: %w", podFailureGracePeriodAnnKey, err)
+		}
+	}
+
+	podMatcher := ctlres.APIVersionKindMatcher{APIVersion: "v1", Kind: "Pod"}
+
+	for _, res := range f.associatedRs {
+		if !podMatcher.Matches(res) {
+			continue
+		}
+
+		pod := corev1.Pod{}
+
+		err := res.AsTypedObj(&pod)
+		if err != nil {
+			return nil, err
+		}
+
+		// Pods that are being replaced are not relevant
+		if pod.DeletionTimestamp != nil || !f.isCurrentPod(pod) {
+			continue
+		}
+
+		if f.now().Sub(pod.CreationTimestamp.Time) < gracePeriod {
+			continue
+		}
+
+		reason := f.failureReason(pod, gracePeriod)
+		if len(reason) > 0 {
+			return &DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+				"Pod '%s' is failing: %s", pod.Name, reason)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (f podFailures) failureReason(pod corev1.Pod, gracePeriod time.Duration) string {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, st := range statuses {
+		if st.State.Waiting != nil {
+			if _, found := podFailureWaitingReasons[st.State.Waiting.Reason]; found {
+				msg := fmt.Sprintf("%s (container: %s)", st.State.Waiting.Reason, st.Name)
+				if len(st.State.Waiting.Message) > 0 {
+					msg += fmt.Sprintf(" (message: %s)", st.State.Waiting.Message)
+				}
+				return msg
+			}
+		}
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == corev1.PodReasonUnschedulable {
+			// Scheduling may only be attempted well after Pod creation
+			// (e.g. while waiting for cluster autoscaler)
+			if f.now().Sub(cond.LastTransitionTime.Time) < gracePeriod {
+				continue
+			}
+			msg := cond.Reason
+			if len(cond.Message) > 0 {
+				msg += fmt.Sprintf(" (message: %s)", cond.Message)
+			}
+			return msg
+		}
+	}
+
+	return ""
+}
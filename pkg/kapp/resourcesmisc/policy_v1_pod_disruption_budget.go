@@ -0,0 +1,50 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	policyV1PDBDisableWaitAnnKey = "kapp.k14s.io/disable-pod-disruption-budget-wait" // valid value is ''
+)
+
+type PolicyV1PDB struct {
+	resource ctlres.Resource
+}
+
+func NewPolicyV1PDB(resource ctlres.Resource) *PolicyV1PDB {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "policy/v1",
+		Kind:       "PodDisruptionBudget",
+	}
+	if matcher.Matches(resource) {
+		if _, found := resource.Annotations()[policyV1PDBDisableWaitAnnKey]; !found {
+			return &PolicyV1PDB{resource}
+		}
+	}
+	return nil
+}
+
+func (s PolicyV1PDB) IsDoneApplying() DoneApplyState {
+	pdb := policyv1.PodDisruptionBudget{}
+
+	err := s.resource.AsTypedObj(&pdb)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	// Budget with no disruptions allowed is still valid,
+	// hence only wait for controller to process it
+	if pdb.Generation != pdb.Status.ObservedGeneration {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for generation %d to be observed", pdb.Generation)}
+	}
+
+	return DoneApplyState{Done: true, Successful: true}
+}
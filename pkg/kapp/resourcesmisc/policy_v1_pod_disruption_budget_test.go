@@ -0,0 +1,93 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestPolicyV1PDB(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expectedState ctlresm.DoneApplyState
+	}{
+		{
+			name: "budget not yet observed, waiting",
+			data: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+  generation: 2
+spec:
+  minAvailable: 1
+status:
+  observedGeneration: 1
+`,
+			expectedState: ctlresm.DoneApplyState{Done: false, Message: "Waiting for generation 2 to be observed"},
+		},
+		{
+			name: "budget observed, done",
+			data: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+  generation: 2
+spec:
+  minAvailable: 1
+status:
+  observedGeneration: 2
+  disruptionsAllowed: 1
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+		{
+			name: "budget observed without allowed disruptions, done",
+			data: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+  generation: 1
+spec:
+  minAvailable: 3
+status:
+  observedGeneration: 1
+  disruptionsAllowed: 0
+`,
+			expectedState: ctlresm.DoneApplyState{Done: true, Successful: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pdb := buildPDB(tc.data, t)
+			require.NotNil(t, pdb)
+			require.Equal(t, tc.expectedState, pdb.IsDoneApplying())
+		})
+	}
+}
+
+func TestPolicyV1PDBDisableWait(t *testing.T) {
+	data := `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+  annotations:
+    kapp.k14s.io/disable-pod-disruption-budget-wait: ""
+`
+	require.Nil(t, buildPDB(data, t))
+}
+
+func buildPDB(resourcesBs string, t *testing.T) *ctlresm.PolicyV1PDB {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewPolicyV1PDB(newResources[0])
+}
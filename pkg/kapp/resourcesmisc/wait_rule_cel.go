@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// WaitRuleCEL evaluates compiled CEL expressions against resource
+// and its associated resources. Expressions that fail to evaluate
+// (e.g. due to missing status fields) are treated as not matched.
+type WaitRuleCEL struct {
+	Programs ctlconf.WaitRuleCELPrograms
+}
+
+func (t WaitRuleCEL) Apply(res ctlres.Resource, associatedRs []ctlres.Resource) *WaitRuleContractV1ResultDetails {
+	associatedObjs := []interface{}{}
+	for _, aRes := range associatedRs {
+		associatedObjs = append(associatedObjs, aRes.DeepCopyRaw())
+	}
+
+	vars := map[string]interface{}{
+		ctlconf.WaitRuleCELResourceVar:            res.DeepCopyRaw(),
+		ctlconf.WaitRuleCELAssociatedResourcesVar: associatedObjs,
+	}
+
+	var lastErr error
+
+	matched, err := t.eval(t.Programs.Failure, vars)
+	if err != nil {
+		lastErr = fmt.Errorf("failure expression: %w", err)
+	} else if matched {
+		return &WaitRuleContractV1ResultDetails{Done: true, Successful: false,
+			Message: "Encountered failure (cel failure expression is true)"}
+	}
+
+	matched, err = t.eval(t.Programs.Success, vars)
+	if err != nil {
+		lastErr = fmt.Errorf("success expression: %w", err)
+	} else if matched {
+		return &WaitRuleContractV1ResultDetails{Done: true, Successful: true,
+			Message: "Encountered success (cel success expression is true)"}
+	}
+
+	matched, err = t.eval(t.Programs.InProgress, vars)
+	if err != nil {
+		lastErr = fmt.Errorf("inProgress expression: %w", err)
+	} else if matched {
+		return &WaitRuleContractV1ResultDetails{Message: "In progress (cel inProgress expression is true)"}
+	}
+
+	if lastErr != nil {
+		return &WaitRuleContractV1ResultDetails{Message: fmt.Sprintf(
+			"No failing or successful cel expression matched (last error: %s)", lastErr)}
+	}
+
+	return &WaitRuleContractV1ResultDetails{Message: "No failing or successful cel expression matched"}
+}
+
+func (t WaitRuleCEL) eval(program cel.Program, vars map[string]interface{}) (bool, error) {
+	if program == nil {
+		return false, nil
+	}
+
+	val, _, err := program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := val.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("Expected bool result, but was %T", val.Value())
+	}
+
+	return result, nil
+}
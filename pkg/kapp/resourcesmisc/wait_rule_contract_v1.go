@@ -4,6 +4,7 @@
 package resourcesmisc
 
 import (
+	"encoding/json"
 	"fmt"
 
 	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
@@ -16,6 +17,11 @@ import (
 type WaitRuleContractV1 struct {
 	ResourceMatcher ctlres.ResourceMatcher
 	Starlark        string
+
+	// When enabled, is_done function receives associated resources
+	// as a second argument (list of resources)
+	IncludeAssociatedRs bool
+	AssociatedRs        []ctlres.Resource
 }
 
 type waitRuleContractV1Result struct {
@@ -54,6 +60,21 @@ func (t WaitRuleContractV1) evalYtt(res ctlres.Resource) (*WaitRuleContractV1Res
 		files.MustNewFileFromSource(files.NewBytesSource("config.yml", t.getConfigYAML())),
 	}
 
+	if t.IncludeAssociatedRs {
+		associatedObjs := []interface{}{}
+		for _, aRes := range t.AssociatedRs {
+			associatedObjs = append(associatedObjs, aRes.DeepCopyRaw())
+		}
+
+		associatedBs, err := json.Marshal(associatedObjs)
+		if err != nil {
+			return nil, fmt.Errorf("Serializing associated resources: %w", err)
+		}
+
+		filesToProcess = append(filesToProcess, files.MustNewFileFromSource(
+			files.NewBytesSource("associated_resources.json", associatedBs)))
+	}
+
 	out := opts.RunWithFiles(cmdtpl.Input{Files: filesToProcess}, ui.NewTTY(false))
 	if out.Err != nil {
 		return nil, fmt.Errorf("Evaluating: %w", out.Err)
@@ -79,6 +100,17 @@ func (t WaitRuleContractV1) evalYtt(res ctlres.Resource) (*WaitRuleContractV1Res
 }
 
 func (t WaitRuleContractV1) getConfigYAML() []byte {
+	if t.IncludeAssociatedRs {
+		config := `
+#@ load("resource.star", "is_done")
+#@ load("@ytt:data", "data")
+#@ load("@ytt:json", "json")
+
+result: #@ is_done(data.values, json.decode(data.read("associated_resources.json")))
+`
+		return []byte(config)
+	}
+
 	config := `
 #@ load("resource.star", "is_done")
 #@ load("@ytt:data", "data")
@@ -0,0 +1,97 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppChangeRollback(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: %s
+`
+
+	name := "test-app-change-rollback"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app recording resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-record-resources"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val1"))})
+	})
+
+	logger.Section("deploy app with changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-record-resources"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val2"))})
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val2", out)
+	})
+
+	var firstChangeName string
+
+	logger.Section("find first app change", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 2, len(resp.Tables[0].Rows), "Expected to have 2 app-changes")
+		firstChangeName = resp.Tables[0].Rows[1]["name"]
+	})
+
+	var rollbackChangeName string
+
+	logger.Section("rollback to first app change", func() {
+		kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--to", firstChangeName}, RunOpts{IntoNs: true})
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val1", out)
+
+		out, _ = kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		rollbackChangeName = resp.Tables[0].Rows[0]["name"]
+	})
+
+	logger.Section("rollback to app change without recorded resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val3"))})
+
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		lastChangeName := resp.Tables[0].Rows[0]["name"]
+
+		_, err := kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--to", lastChangeName}, RunOpts{IntoNs: true, AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "to have recorded resources")
+	})
+
+	logger.Section("rollback to rollback app change", func() {
+		kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--to", rollbackChangeName}, RunOpts{IntoNs: true})
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val1", out)
+	})
+}
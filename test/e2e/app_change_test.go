@@ -240,3 +240,49 @@ type yamlSubset struct {
 	LastChange lastChange `yaml:"lastChange"`
 	UsedGKs    []usedGK   `yaml:"usedGKs"`
 }
+
+func TestAppChangeDiff(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: %s
+`
+
+	name := "test-app-change-diff"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app twice recording resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-record-resources"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val1"))})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-record-resources"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val2"))})
+	})
+
+	logger.Section("diff app changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 2, len(resp.Tables[0].Rows), "Expected to have 2 app-changes")
+
+		out, _ = kapp.RunWithOpts([]string{"app-change", "diff", "-a", name,
+			resp.Tables[0].Rows[1]["name"], resp.Tables[0].Rows[0]["name"], "--changes"}, RunOpts{})
+
+		require.Contains(t, out, "-   key: val1")
+		require.Contains(t, out, "+   key: val2")
+		require.Contains(t, out, "Op:      0 create, 0 delete, 1 update, 0 noop, 0 exists")
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	config := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+confirmationRules:
+- name: delete-unprotected-config
+  ops: [delete]
+  resourceMatchers:
+  - andMatcher:
+      matchers:
+      - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+      - notMatcher:
+          matcher:
+            hasAnnotationMatcher:
+              keys: [kapp.k14s.io/delete-strategy]
+`
+
+	yaml1 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first-cm
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second-cm
+`
+
+	yaml2 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second-cm
+`
+
+	name := "test-confirmation-rules"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--allow-dangerous-change", "delete-unprotected-config"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	yaml2Path := filepath.Join(dir, "resources.yml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+	require.NoError(t, os.WriteFile(yaml2Path, []byte(yaml2), 0600))
+
+	logger.Section("deploy without dangerous changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-f", configPath, "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("fail to delete in non-interactive mode", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", yaml2Path, "-f", configPath, "-a", name},
+			RunOpts{IntoNs: true, AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --allow-dangerous-change=delete-unprotected-config to be specified "+
+			"to apply 1 change(s) matching confirmation rule 'delete-unprotected-config' in non-interactive mode")
+
+		kubectl.Run([]string{"get", "configmap", "first-cm"})
+	})
+
+	logger.Section("fail when typed confirmation does not match", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", yaml2Path, "-f", configPath, "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, Interactive: true, StdinReader: strings.NewReader("wrong\n")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Changes matching confirmation rule 'delete-unprotected-config' were not confirmed")
+
+		kubectl.Run([]string{"get", "configmap", "first-cm"})
+	})
+
+	logger.Section("fail with unknown allowed rule", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", yaml2Path, "-f", configPath, "-a", name,
+			"--allow-dangerous-change", "unknown-rule"}, RunOpts{IntoNs: true, AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --allow-dangerous-change to reference one of confirmation rules (unknown: 'unknown-rule')")
+	})
+
+	logger.Section("delete with explicitly allowed dangerous change", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", yaml2Path, "-f", configPath, "-a", name,
+			"--allow-dangerous-change", "delete-unprotected-config"}, RunOpts{IntoNs: true})
+		require.Contains(t, out, "Allowed 1 dangerous change(s) matching confirmation rule 'delete-unprotected-config'")
+
+		_, err := kubectl.RunWithOpts([]string{"get", "configmap", "first-cm"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "NotFound")
+	})
+}
+
+func TestConfirmationRulesOnDelete(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	config := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+confirmationRules:
+- name: delete-config
+  ops: [delete]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+`
+
+	overrideConfig := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+confirmationRules:
+- name: delete-any-config
+  ops: [delete]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+`
+
+	yaml1 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first-cm
+`
+
+	name := "test-confirmation-rules-on-delete"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--allow-dangerous-change", "delete-config"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	overrideConfigPath := filepath.Join(dir, "override-config.yml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+	require.NoError(t, os.WriteFile(overrideConfigPath, []byte(overrideConfig), 0600))
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-f", configPath, "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("fail to delete in non-interactive mode with recorded config", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --allow-dangerous-change=delete-config to be specified "+
+			"to apply 1 change(s) matching confirmation rule 'delete-config' in non-interactive mode")
+
+		kubectl.Run([]string{"get", "configmap", "first-cm"})
+	})
+
+	logger.Section("fail to delete in non-interactive mode with given config", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name, "-f", overrideConfigPath}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --allow-dangerous-change=delete-any-config to be specified "+
+			"to apply 1 change(s) matching confirmation rule 'delete-any-config' in non-interactive mode")
+
+		kubectl.Run([]string{"get", "configmap", "first-cm"})
+	})
+
+	logger.Section("delete with explicitly allowed dangerous change", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name,
+			"--allow-dangerous-change", "delete-config"}, RunOpts{})
+		require.Contains(t, out, "Allowed 1 dangerous change(s) matching confirmation rule 'delete-config'")
+
+		_, err := kubectl.RunWithOpts([]string{"get", "configmap", "first-cm"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "NotFound")
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployDryRunServer(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: app1
+`
+
+	invalidYAML := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+spec:
+  ports:
+  - port: 80
+    protocol: INVALID
+  selector:
+    app: app1
+`
+
+	name := "test-deploy-dry-run-server"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("dry run new app", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--dry-run=server", "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 1, len(resp.Tables[0].Rows))
+		require.Contains(t, resp.Tables[0].Rows[0]["dry_run"], "defaulted")
+		require.Contains(t, strings.Join(resp.Tables[0].Notes, "\n"), ".spec.ports[0].protocol")
+
+		_, err := kubectl.RunWithOpts([]string{"get", "service", "svc1"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected service to not be created")
+
+		_, err = kapp.RunWithOpts([]string{"inspect", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected app to not be recorded")
+	})
+
+	logger.Section("dry run rejected change", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--dry-run=server"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(invalidYAML)})
+
+		require.Error(t, err)
+		require.Contains(t, out, "rejected (invalid)")
+		require.Contains(t, err.Error(), "Server-side dry run rejected 1 changes")
+
+		out = kubectl.Run([]string{"get", "service", "svc1", "-o", "jsonpath={.spec.ports[0].protocol}"})
+		require.Equal(t, "TCP", out)
+	})
+
+	logger.Section("invalid dry run value", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--dry-run=client"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --dry-run to be one of")
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployPlan(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+data:
+  key: val1
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm3
+data:
+  key: val1
+`
+
+	name := "test-deploy-plan"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	planFile, err := os.CreateTemp("", "kapp-test-deploy-plan")
+	require.NoError(t, err)
+
+	defer os.Remove(planFile.Name())
+
+	logger.Section("plan for new app", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--plan-output", planFile.Name()},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "to exist before creating a plan")
+	})
+
+	logger.Section("plan and apply changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--plan-output", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, "Wrote plan with 3 changes")
+
+		out = kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val1", out)
+
+		kapp.RunWithOpts([]string{"deploy", "-a", name, "--plan-input", planFile.Name()}, RunOpts{IntoNs: true})
+
+		out = kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val2", out)
+
+		kubectl.Run([]string{"get", "configmap", "cm3"})
+
+		_, err := kubectl.RunWithOpts([]string{"get", "configmap", "cm2"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected configmap to be deleted")
+	})
+
+	logger.Section("refuse to apply plan after drift", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--plan-output", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kubectl.Run([]string{"patch", "configmap", "cm1", "--type", "merge", "-p", `{"data":{"key":"drifted"}}`})
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-a", name, "--plan-input", planFile.Name()},
+			RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to apply plan since cluster state changed after planning")
+		require.Contains(t, err.Error(), "configmap/cm1")
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "drifted", out)
+	})
+
+	logger.Section("apply plan recording resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--plan-output", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		kapp.RunWithOpts([]string{"deploy", "-a", name, "--plan-input", planFile.Name(), "--app-changes-record-resources"},
+			RunOpts{IntoNs: true})
+
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		planChangeName := resp.Tables[0].Rows[0]["name"]
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--to", planChangeName}, RunOpts{IntoNs: true})
+
+		out = kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val2", out)
+
+		kubectl.Run([]string{"get", "configmap", "cm3"})
+	})
+}
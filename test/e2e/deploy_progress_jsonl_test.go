@@ -0,0 +1,155 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployProgressJSONL(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val1
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job1
+spec:
+  template:
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["/bin/sh", "-c", "exit 0"]
+      restartPolicy: Never
+`
+
+	name := "test-deploy-progress-jsonl"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type progressEvent struct {
+		Type   string
+		Change *struct {
+			Kind string
+			Name string
+		}
+		State      string
+		Successful *bool
+	}
+
+	readEvents := func(path string) []progressEvent {
+		bs, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var events []progressEvent
+
+		for _, line := range strings.Split(strings.TrimSuffix(string(bs), "\n"), "\n") {
+			var event progressEvent
+			require.NoError(t, json.Unmarshal([]byte(line), &event), "Expected line to be valid JSON: %s", line)
+			events = append(events, event)
+		}
+
+		return events
+	}
+
+	logger.Section("deploy with jsonl progress", func() {
+		progressPath := filepath.Join(t.TempDir(), "progress.jsonl")
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--progress-format", "jsonl", "--progress-file", progressPath, "--tty=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Contains(t, out, "Changes")
+
+		events := readEvents(progressPath)
+
+		countByType := map[string]int{}
+		var jobWaitStates []string
+
+		for _, event := range events {
+			countByType[event.Type]++
+			if event.Type == "wait-state" && event.Change.Kind == "Job" {
+				jobWaitStates = append(jobWaitStates, event.State)
+			}
+		}
+
+		require.Equal(t, 2, countByType["apply-start"])
+		require.Equal(t, 2, countByType["apply-finish"])
+		require.Contains(t, jobWaitStates, "ok")
+
+		lastEvent := events[len(events)-1]
+		require.Equal(t, "outcome", lastEvent.Type)
+		require.True(t, *lastEvent.Successful)
+	})
+
+	logger.Section("deploy failing preflight check with jsonl progress", func() {
+		config := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: noConfigMaps
+  ytt:
+    funcContractV1:
+      rule.star: |
+        def check(change):
+          if change.newResource["kind"] == "ConfigMap":
+            return {"passed": False, "message": "ConfigMaps are not allowed"}
+          end
+          return {"passed": True}
+        end
+`
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.yml")
+		progressPath := filepath.Join(dir, "progress.jsonl")
+
+		require.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-f", configPath, "-a", name,
+			"--progress-format", "jsonl", "--progress-file", progressPath},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(strings.Replace(yaml, "val1", "val2", 1))})
+		require.Error(t, err)
+
+		events := readEvents(progressPath)
+		require.Len(t, events, 1)
+		require.Equal(t, "outcome", events[0].Type)
+		require.False(t, *events[0].Successful)
+	})
+
+	logger.Section("jsonl progress without progress file", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--progress-format", "jsonl"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --progress-file to be specified")
+	})
+
+	logger.Section("invalid progress format", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--progress-format", "xml"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --progress-format to be one of")
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployRollbackOnFailure(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val1
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val2
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: failing-job
+spec:
+  backoffLimit: 0
+  template:
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["/bin/sh", "-c", "exit 1"]
+      restartPolicy: Never
+`
+
+	name := "test-deploy-rollback-on-failure"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy successfully", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--rollback-on-failure"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("deploy failing change", func() {
+		reportPath := filepath.Join(t.TempDir(), "report.html")
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--rollback-on-failure", "--diff-html-report", reportPath},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rolled back to app change")
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "val1", out)
+
+		_, err = kubectl.RunWithOpts([]string{"get", "job", "failing-job"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected job to be deleted")
+
+		reportBs, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+
+		// Report shows failed deploy changes and not rollback changes
+		require.Contains(t, string(reportBs), "create job/failing-job")
+		require.NotContains(t, string(reportBs), "delete job/failing-job")
+	})
+
+	logger.Section("check recorded app changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 3, len(resp.Tables[0].Rows), "Expected to have 3 app-changes")
+
+		require.True(t, strings.HasPrefix(resp.Tables[0].Rows[0]["description"], "rollback: "))
+		require.Equal(t, "true", resp.Tables[0].Rows[0]["successful"])
+
+		require.True(t, strings.HasPrefix(resp.Tables[0].Rows[1]["description"], "update: "))
+		require.Equal(t, "false", resp.Tables[0].Rows[1]["successful"])
+	})
+}
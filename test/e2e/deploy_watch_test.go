@@ -0,0 +1,102 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployWatch(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yamlTpl := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: %s
+`
+
+	name := "test-deploy-watch"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir, err := os.MkdirTemp("", "kapp-test-deploy-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeConfig := func(val string) {
+		err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(fmt.Sprintf(yamlTpl, val)), 0600)
+		require.NoError(t, err)
+	}
+
+	waitForVal := func(val string) {
+		for i := 0; i < 60; i++ {
+			out, err := kubectl.RunWithOpts([]string{"get", "cm", "cm1", "-o", "jsonpath={.data.key}"}, RunOpts{AllowError: true})
+			if err == nil && strings.TrimSpace(out) == val {
+				return
+			}
+			time.Sleep(1 * time.Second)
+		}
+		t.Fatalf("Expected config map to have value '%s'", val)
+	}
+
+	writeConfig("val1")
+
+	cancelCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	var out string
+
+	go func() {
+		defer close(doneCh)
+		out, _ = kapp.RunWithOpts([]string{"deploy", "-f", dir, "-a", name, "--watch", "--watch-coalesce", "1s"},
+			RunOpts{IntoNs: true, AllowError: true, CancelCh: cancelCh})
+	}()
+
+	logger.Section("initial deploy", func() {
+		waitForVal("val1")
+	})
+
+	logger.Section("deploy again after file change", func() {
+		writeConfig("val2")
+		waitForVal("val2")
+	})
+
+	logger.Section("stop watching", func() {
+		close(cancelCh)
+
+		select {
+		case <-doneCh:
+		case <-time.After(30 * time.Second):
+			t.Fatalf("Expected watch to stop after interrupt")
+		}
+
+		require.Contains(t, out, "Watch cycle 1 (initial): succeeded")
+		require.Contains(t, out, "Watch cycle 2 (changed ")
+	})
+
+	logger.Section("invalid usage", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--watch"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(fmt.Sprintf(yamlTpl, "val3"))})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --watch to not be used with stdin")
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFormat(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: simple-cm
+data:
+  key1: val1
+  key2: val2
+`
+
+	yaml2 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: simple-cm
+data:
+  key1: val1-updated
+`
+
+	name := "test-diff-format"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy initial", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("json format", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-format", "json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, `"applyOp": "update"`)
+		require.Contains(t, out, `"path": "/data/key1"`)
+		require.Contains(t, out, `"oldValue": "val1"`)
+		require.Contains(t, out, `"newValue": "val1-updated"`)
+		require.Contains(t, out, `"path": "/data/key2"`)
+	})
+
+	logger.Section("json-patch format", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-format", "json-patch"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, `"jsonPatch": [`)
+		require.Contains(t, out, `"op": "remove"`)
+		require.Contains(t, out, `"path": "/data/key2"`)
+	})
+
+	logger.Section("unified format", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-format", "unified"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, "--- a/"+env.Namespace+"/ConfigMap/simple-cm.yml\n+++ b/"+env.Namespace+"/ConfigMap/simple-cm.yml\n")
+		require.Contains(t, out, "\n-  key1: val1\n")
+		require.Contains(t, out, "\n-  key2: val2\n")
+		require.Contains(t, out, "\n+  key1: val1-updated\n")
+	})
+
+	logger.Section("unknown format", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-format", "unknown"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected diff format to be one of: text, json, unified, json-patch, semantic (given: 'unknown')")
+	})
+}
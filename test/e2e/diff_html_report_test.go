@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffHTMLReport(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first-cm
+  annotations:
+    kapp.k14s.io/change-group: first
+data:
+  key: val
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second-secret
+  annotations:
+    kapp.k14s.io/change-rule: upsert after upserting first
+stringData:
+  password: super-secret-value
+`
+
+	name := "test-diff-html-report"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	reportPath := filepath.Join(t.TempDir(), "report.html")
+
+	logger.Section("write report without deploying", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-html-report", reportPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		reportBs, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+
+		report := string(reportBs)
+
+		require.Contains(t, report, "Op: 2 create, 0 delete, 0 update, 0 noop, 0 exists")
+		require.Contains(t, report, "<h3>Section 1</h3>")
+		require.Contains(t, report, "<h3>Section 2</h3>")
+		require.Contains(t, report, "(upsert) configmap/first-cm (v1) namespace: "+env.Namespace)
+		require.Contains(t, report, "create configmap/first-cm (v1) namespace: "+env.Namespace)
+		require.Contains(t, report, "create secret/second-secret (v1) namespace: "+env.Namespace)
+		require.NotContains(t, report, "super-secret-value")
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrift(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+data:
+  key: val2
+`
+
+	name := "test-drift"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("no drift", func() {
+		out, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status", "--json"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Exiting after detecting no drift (exit status 2)")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 2)
+		for _, row := range resp.Tables[0].Rows {
+			require.Equal(t, "none", row["drift"])
+		}
+	})
+
+	logger.Section("drift after modification on cluster", func() {
+		kubectl.Run([]string{"patch", "cm", "cm1", "--type=merge", "-p", `{"data":{"key":"changed"}}`})
+
+		out, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status", "--json"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Exiting after detecting drift (exit status 3)")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		driftByName := map[string]string{}
+		fieldsByName := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			driftByName[row["name"]] = row["drift"]
+			fieldsByName[row["name"]] = row["fields"]
+		}
+
+		require.Equal(t, "drifted", driftByName["cm1"])
+		require.Equal(t, "/data/key", fieldsByName["cm1"])
+		require.Equal(t, "none", driftByName["cm2"])
+	})
+
+	logger.Section("drift with changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"drift", "-a", name, "-c"}, RunOpts{})
+		require.Contains(t, out, "@@ drifted configmap/cm1")
+		require.Contains(t, out, "key: changed")
+	})
+}
+
+func TestDriftWithRecordedConfig(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [data, managed]
+  type: copy
+  sources: [existing]
+  resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: cm1}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: val1
+  managed: val1
+`
+
+	emptyConfigYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+`
+
+	name := "test-drift-with-recorded-config"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__ns__", env.Namespace))})
+	})
+
+	logger.Section("no drift when rebase rule from recorded config covers modified field", func() {
+		kubectl.Run([]string{"patch", "cm", "cm1", "--type=merge", "-p", `{"data":{"managed":"changed"}}`})
+
+		out, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status", "--json"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Exiting after detecting no drift (exit status 2)")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "none", resp.Tables[0].Rows[0]["drift"])
+	})
+
+	logger.Section("drift when given config overrides recorded config", func() {
+		out, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status", "--json", "-f", "-"},
+			RunOpts{AllowError: true, StdinReader: strings.NewReader(emptyConfigYAML)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Exiting after detecting drift (exit status 3)")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "drifted", resp.Tables[0].Rows[0]["drift"])
+		require.Equal(t, "/data/managed", resp.Tables[0].Rows[0]["fields"])
+	})
+
+	logger.Section("drift when field not covered by rebase rule is modified", func() {
+		kubectl.Run([]string{"patch", "cm", "cm1", "--type=merge", "-p", `{"data":{"key":"changed"}}`})
+
+		out, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status", "--json"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Exiting after detecting drift (exit status 3)")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Equal(t, "drifted", resp.Tables[0].Rows[0]["drift"])
+		require.Equal(t, "/data/key", resp.Tables[0].Rows[0]["fields"])
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightCRDUpgradeSafetySchemaChange(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	testName := "preflightcrdupgradesafetyschemachange"
+
+	crdTpl := `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: memcacheds.__test-name__.example.com
+spec:
+  group: __test-name__.example.com
+  names:
+    kind: Memcached
+    listKind: MemcachedList
+    plural: memcacheds
+    singular: memcached
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            type: object
+            properties:
+__spec-properties__
+        type: object
+    served: true
+    storage: true
+`
+
+	crdWithSpecProps := func(props string) string {
+		crd := strings.ReplaceAll(crdTpl, "__test-name__", testName)
+		return strings.ReplaceAll(crd, "__spec-properties__", props)
+	}
+
+	base := crdWithSpecProps(`
+              replicas:
+                type: integer
+                default: 1
+              size:
+                type: string
+                enum: [small, large]`)
+
+	appName := "preflight-crdupgradesafety-schema-app"
+
+	crAppName := "preflight-crdupgradesafety-schema-cr-app"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", crAppName})
+		kapp.Run([]string{"delete", "-a", appName})
+		RemoveClusterResource(t, "ns", testName, "", kubectl)
+	}
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-a", appName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(base)})
+
+	logger.Section("deploy app with CRD update that removes field and narrows enum, preflight check enabled, should error", func() {
+		update := crdWithSpecProps(`
+              size:
+                type: string
+                enum: [small]`)
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=CRDUpgradeSafety", "-a", appName, "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(update), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "\"NoExistingFieldRemoved\" validation failed: version \"v1alpha1\": field \".spec.replicas\": removed")
+		require.Contains(t, err.Error(), "\"NoEnumNarrowed\" validation failed: version \"v1alpha1\": field \".spec.size\": enum values removed: \"large\"")
+	})
+
+	logger.Section("deploy app with CRD update that changes default, validation configured to warn, should succeed", func() {
+		update := crdWithSpecProps(`
+              replicas:
+                type: integer
+                default: 2
+              size:
+                type: string
+                enum: [small, large]`) + `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: CRDUpgradeSafety
+  config:
+    validations:
+      NoDefaultValueChange: warn
+`
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "--preflight=CRDUpgradeSafety", "-a", appName, "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(update)})
+		require.NoError(t, err)
+		require.Contains(t, out, "Warning: CustomResourceDefinition memcacheds."+testName+".example.com upgrade safety validation \"NoDefaultValueChange\" reported")
+	})
+
+	logger.Section("deploy app with CRD update that makes existing custom resource invalid, should error", func() {
+		cr := strings.ReplaceAll(`
+---
+apiVersion: __test-name__.example.com/v1alpha1
+kind: Memcached
+metadata:
+  name: cr1
+spec:
+  size: large
+`, "__test-name__", testName)
+
+		kapp.RunWithOpts([]string{"deploy", "-a", crAppName, "-f", "-"}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(cr)})
+
+		update := crdWithSpecProps(`
+              replicas:
+                type: integer
+                default: 2
+              size:
+                type: string
+                enum: [small, large]
+                pattern: "^s"`) + `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: CRDUpgradeSafety
+  config:
+    existingResourcesSampleSize: 10
+    validations:
+      NoConstraintTightened: warn
+`
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=CRDUpgradeSafety", "-a", appName, "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(update), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "\"NoInvalidExistingResources\" validation failed: version \"v1alpha1\": resource \""+env.Namespace+"/cr1\" would fail validation")
+		require.Contains(t, err.Error(), "field \".spec.size\": value does not match pattern \"^s\"")
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightPermissionValidationRulesReview(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	testName := "preflight-permission-validation-rules-review"
+
+	base := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: __test-name__
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: scoped-sa
+  namespace: __ns__
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: scoped-sa
+  namespace: __ns__
+  annotations:
+    kubernetes.io/service-account.name: scoped-sa
+type: kubernetes.io/service-account-token
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: __test-name__
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["*"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["list"]
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get", "list"]
+- apiGroups: ["rbac.authorization.k8s.io"]
+  resources: ["roles", "rolebindings"]
+  verbs: ["get", "list"]
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: __test-name__
+subjects:
+- kind: ServiceAccount
+  name: scoped-sa
+  namespace: __ns__
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: __test-name__
+`
+
+	base = strings.ReplaceAll(base, "__test-name__", testName)
+	base = strings.ReplaceAll(base, "__ns__", env.Namespace)
+	baseName := "preflight-permission-validation-base-app"
+	appName := "preflight-permission-validation-app"
+	scopedContext := "scoped-context"
+	scopedUser := "scoped-user"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", baseName})
+		kapp.Run([]string{"delete", "-a", appName})
+		RemoveClusterResource(t, "ns", testName, "", kubectl)
+	}
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-a", baseName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(base)})
+	cleanUpContext := ScopedContext(t, kubectl, testName, scopedContext, scopedUser)
+	defer cleanUpContext()
+
+	resources := `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: __test-name__
+  namespace: __test-name__
+spec:
+  containers:
+  - name: simple-app
+    image: docker.io/dkalinin/k8s-simple-app@sha256:4c8b96d4fffdfae29258d94a22ae4ad1fe36139d47288b8960d9958d1e63a9d0
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: __test-name__
+  namespace: __test-name__
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: __test-name__
+  namespace: __test-name__
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: PermissionValidation
+  config:
+    mode: rulesReview
+`
+	resources = strings.ReplaceAll(resources, "__test-name__", testName)
+
+	logger.Section("attempt to deploy app with missing permissions and report all of them", func() {
+		out, err := kapp.RunWithOpts([]string{"deploy", "--preflight=PermissionValidation", "-a", appName, "-f", "-", fmt.Sprintf("--kubeconfig-context=%s", scopedContext)},
+			RunOpts{StdinReader: strings.NewReader(resources), AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "running preflight check \"PermissionValidation\": missing 6 permissions")
+		require.Contains(t, err.Error(), "not permitted to \"create\" /v1, Resource=pods")
+		require.Contains(t, err.Error(), "not permitted to \"patch\" /v1, Resource=secrets")
+		require.NotContains(t, err.Error(), "Resource=configmaps")
+
+		require.Contains(t, out, "Missing permissions")
+		require.Contains(t, out, "Summary: 6 missing permissions")
+
+		NewMissingClusterResource(t, "pod", testName, testName, kubectl)
+	})
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: PermissionValidation
+  config:
+    mode: unknown
+`
+
+	logger.Section("attempt to deploy app with unknown mode", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=PermissionValidation", "-a", appName, "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(config), AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expected \"mode\" config to be one of: accessReview, rulesReview (given: 'unknown')")
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBACForApp(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	testName := "rbac-for-app"
+
+	base := `
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: scoped-sa
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: scoped-sa
+  annotations:
+    kubernetes.io/service-account.name: scoped-sa
+type: kubernetes.io/service-account-token
+`
+
+	app := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: __test-name__
+data:
+  key: value
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: __test-name__
+spec:
+  selector:
+    matchLabels:
+      app: __test-name__
+  template:
+    metadata:
+      labels:
+        app: __test-name__
+    spec:
+      containers:
+      - name: simple-app
+        image: docker.io/dkalinin/k8s-simple-app@sha256:4c8b96d4fffdfae29258d94a22ae4ad1fe36139d47288b8960d9958d1e63a9d0
+`
+	app = strings.ReplaceAll(app, "__test-name__", testName)
+
+	baseName := testName + "-base"
+	rbacName := testName + "-rbac"
+	appName := testName + "-app"
+	scopedContext := "scoped-context"
+	scopedUser := "scoped-user"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+		kapp.Run([]string{"delete", "-a", rbacName})
+		kapp.Run([]string{"delete", "-a", baseName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-a", baseName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(base)})
+	cleanUpContext := ScopedContext(t, kubectl, testName, scopedContext, scopedUser)
+	defer cleanUpContext()
+
+	var rbac string
+
+	logger.Section("generate rbac for resources from files", func() {
+		rbac, _ = kapp.RunWithOpts([]string{"tools", "rbac-for-app", "-f", "-", "--rbac-name", testName,
+			"--service-account", env.Namespace + "/scoped-sa"}, RunOpts{StdinReader: strings.NewReader(app)})
+
+		require.Contains(t, rbac, "kind: Role\n")
+		require.Contains(t, rbac, "kind: RoleBinding\n")
+		require.NotContains(t, rbac, "kind: ClusterRole\n")
+
+		// Deployment itself, its associated ReplicaSets and Pods and pod logs
+		require.Contains(t, rbac, "  - deployments\n")
+		require.Contains(t, rbac, "  - replicasets\n")
+		require.Contains(t, rbac, "  - pods/log\n")
+		// ConfigMaps are applied and used for app record
+		require.Contains(t, rbac, "  - configmaps\n")
+	})
+
+	logger.Section("deploy and delete app using generated rbac", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", rbacName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(rbac)})
+
+		kapp.RunWithOpts([]string{"deploy", "-a", appName, "-f", "-", fmt.Sprintf("--kubeconfig-context=%s", scopedContext)},
+			RunOpts{StdinReader: strings.NewReader(app)})
+
+		NewPresentClusterResource("deployment", testName, env.Namespace, kubectl)
+
+		kapp.RunWithOpts([]string{"delete", "-a", appName, fmt.Sprintf("--kubeconfig-context=%s", scopedContext)}, RunOpts{})
+
+		NewMissingClusterResource(t, "deployment", testName, env.Namespace, kubectl)
+	})
+
+	logger.Section("generate rbac for existing app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", appName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(app)})
+
+		out, _ := kapp.RunWithOpts([]string{"tools", "rbac-for-app", "-a", appName, "--rbac-name", testName}, RunOpts{})
+
+		require.Contains(t, out, "kind: Role\n")
+		require.NotContains(t, out, "kind: RoleBinding\n")
+		require.Contains(t, out, "  - deployments\n")
+		require.Contains(t, out, "  - replicasets\n")
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateServerSideApply(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  annotations:
+    kapp.k14s.io/update-strategy: server-side-apply
+data:
+  key1: %s
+  key2: val
+`
+
+	otherManagerYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key1: other-val
+`
+
+	name := "test-update-server-side-apply"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy and update via server-side apply", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val1"))})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val2"))})
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.metadata.managedFields[*].manager}"})
+		require.Contains(t, out, "kapp")
+
+		out = kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key1}"})
+		require.Equal(t, "val2", out)
+	})
+
+	logger.Section("take ownership of field by another field manager", func() {
+		kubectl.RunWithOpts([]string{"apply", "--server-side", "--force-conflicts", "--field-manager", "other-manager", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(otherManagerYAML)})
+	})
+
+	logger.Section("deploy conflicting change", func() {
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-field-ownership-conflicts"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val3"))})
+
+		require.Error(t, err)
+		require.Contains(t, out, "server-side apply (1 conflicts)")
+		require.Contains(t, err.Error(), "Field ownership conflicts")
+		require.Contains(t, err.Error(), "other-manager")
+	})
+
+	logger.Section("deploy conflicting change with force", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--apply-force-conflicts"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, "val3"))})
+
+		out := kubectl.Run([]string{"get", "configmap", "cm1", "-o", "jsonpath={.data.key1}"})
+		require.Equal(t, "val3", out)
+	})
+}
@@ -4,6 +4,7 @@
 package e2e
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -68,3 +69,77 @@ func TestWaitTimeout(t *testing.T) {
 		require.NoErrorf(t, err, "Expected to be successful without resource timeout")
 	})
 }
+
+func TestWaitTimeoutPerResource(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	jobTpl := `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-job
+  annotations:
+%s
+spec:
+  template:
+    spec:
+      containers:
+      - name: slow-job
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 10"]
+      restartPolicy: Never
+`
+
+	name := "test-wait-timeout-per-resource"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("Resource annotation overrides global resource timeout", func() {
+		yaml := fmt.Sprintf(jobTpl, `    kapp.k14s.io/wait-timeout: 1s`)
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-resource-timeout", "100s"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Containsf(t, err.Error(), "Resource timed out waiting after 1s", "Expected to see timed out, but did not")
+	})
+
+	cleanUp()
+
+	logger.Section("Resource timeout treated as warning", func() {
+		yaml := fmt.Sprintf(jobTpl, `    kapp.k14s.io/wait-timeout: 1s
+    kapp.k14s.io/wait-timeout-action: warn`)
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.NoErrorf(t, err, "Expected timeout to be treated as warning")
+		require.Contains(t, out, "Warning: waiting on reconcile job/slow-job (batch/v1) namespace: "+env.Namespace+
+			": Resource timed out waiting after 1s (continuing since timeout action is warn)")
+	})
+
+	cleanUp()
+
+	logger.Section("Wait rule timeout", func() {
+		config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: batch/v1, kind: Job}
+  timeout: 1s
+`
+		yaml := fmt.Sprintf(jobTpl, `    foo: bar`) + config
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Containsf(t, err.Error(), "Resource timed out waiting after 1s", "Expected to see timed out, but did not")
+	})
+}
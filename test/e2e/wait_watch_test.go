@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitWatch(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep1
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: dep1
+  template:
+    metadata:
+      labels:
+        app: dep1
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 1000"]
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job1
+spec:
+  template:
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 5"]
+      restartPolicy: Never
+`
+
+	name := "test-wait-watch"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy waiting via watches", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-watch", "--wait-watch-resync-interval", "1h"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Contains(t, out, "waiting complete")
+		require.NotContains(t, out, "Falling back to polling")
+	})
+
+	cleanUp()
+
+	logger.Section("deploy waiting via polling", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-watch=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Contains(t, out, "waiting complete")
+	})
+}